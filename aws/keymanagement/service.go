@@ -0,0 +1,110 @@
+package keymanagement
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/lelinu/api_utils/utils/error_utils"
+)
+
+//Service struct, wraps data keys for crypto_utils.EnvelopeEncrypter with an AWS KMS master key
+type Service struct {
+	keyID      string
+	region     string
+	kmsService IKmsService
+}
+
+//NewService this method will return a new instance of KeyManagementService
+func NewService(keyID string, region string) (*Service, *error_utils.ApiError) {
+	var service = &Service{}
+	err := service.init(keyID, region, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+//NewServiceMock this service will return functionality for mocking purposes
+func NewServiceMock(keyID string, region string, kmsService IKmsService) (*Service, *error_utils.ApiError) {
+	var service = &Service{}
+	err := service.init(keyID, region, kmsService)
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+//init will initialize defaults
+func (a *Service) init(keyID string, region string, kmsService IKmsService) *error_utils.ApiError {
+
+	if len(strings.TrimSpace(keyID)) == 0 {
+		return error_utils.NewBadRequestError("Key Management: Key id is empty")
+	}
+
+	if len(strings.TrimSpace(region)) == 0 {
+		return error_utils.NewBadRequestError("Key Management: Region is empty")
+	}
+
+	// assign params
+	a.keyID = keyID
+	a.region = region
+
+	// if it is not mocked load normal kms
+	if kmsService == nil {
+		service, err := a.getKms()
+		if err != nil {
+			return error_utils.NewBadRequestError(fmt.Sprintf("Key Management: Kms err %v", err))
+		}
+		a.kmsService = service
+	} else {
+		a.kmsService = kmsService
+	}
+
+	return nil
+}
+
+//WrapKey this method will encrypt the data key with the kms master key
+func (a *Service) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	output, err := a.kmsService.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(a.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("keyManagement: WrapKey : Unable to encrypt data key with %v, %v", a.keyID, err)
+	}
+
+	return output.CiphertextBlob, nil
+}
+
+//UnwrapKey this method will decrypt the data key, the kms ciphertext blob identifies the master key
+func (a *Service) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	output, err := a.kmsService.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("keyManagement: UnwrapKey : Unable to decrypt data key, %v", err)
+	}
+
+	return output.Plaintext, nil
+}
+
+//getKms will get kms instance
+func (a *Service) getKms() (IKmsService, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(a.region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+
+	// check error
+	if err != nil {
+		return nil, err
+	}
+
+	return kms.New(sess, aws.NewConfig().WithRegion(a.region)), nil
+}
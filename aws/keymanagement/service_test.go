@@ -0,0 +1,114 @@
+package keymanagement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/lelinu/api_utils/utils/crypto_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	funcEncrypt func(input *kms.EncryptInput) (*kms.EncryptOutput, error)
+	funcDecrypt func(input *kms.DecryptInput) (*kms.DecryptOutput, error)
+	keyID       = "alias/my-key"
+	region      = "eu-west-1"
+)
+
+type KmsServiceMock struct{}
+
+func (a KmsServiceMock) EncryptWithContext(ctx aws.Context, input *kms.EncryptInput, opts ...request.Option) (*kms.EncryptOutput, error) {
+	return funcEncrypt(input)
+}
+
+func (a KmsServiceMock) DecryptWithContext(ctx aws.Context, input *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	return funcDecrypt(input)
+}
+
+func TestInitInvalidKeyID(t *testing.T) {
+
+	service, apiErr := NewService("", region)
+
+	assert.NotNil(t, apiErr)
+	assert.EqualValues(t, "Key Management: Key id is empty", apiErr.ErrorMessage)
+	assert.Nil(t, service)
+}
+
+func TestInitInvalidRegion(t *testing.T) {
+
+	service, apiErr := NewService(keyID, "")
+
+	assert.NotNil(t, apiErr)
+	assert.EqualValues(t, "Key Management: Region is empty", apiErr.ErrorMessage)
+	assert.Nil(t, service)
+}
+
+//TestEnvelopeEncryptionWithKmsValid
+func TestEnvelopeEncryptionWithKmsValid(t *testing.T) {
+
+	// reverse the data key to simulate kms wrapping
+	reverse := func(b []byte) []byte {
+		r := make([]byte, len(b))
+		for i := range b {
+			r[i] = b[len(b)-1-i]
+		}
+		return r
+	}
+	funcEncrypt = func(input *kms.EncryptInput) (*kms.EncryptOutput, error) {
+		assert.EqualValues(t, keyID, *input.KeyId)
+		return &kms.EncryptOutput{CiphertextBlob: reverse(input.Plaintext), KeyId: input.KeyId}, nil
+	}
+	funcDecrypt = func(input *kms.DecryptInput) (*kms.DecryptOutput, error) {
+		return &kms.DecryptOutput{Plaintext: reverse(input.CiphertextBlob)}, nil
+	}
+
+	service, apiErr := NewServiceMock(keyID, region, KmsServiceMock{})
+	assert.Nil(t, apiErr)
+
+	encrypter, err := crypto_utils.NewEnvelopeEncrypter(service, crypto_utils.AES256GCM)
+	assert.Nil(t, err)
+
+	ciphertext, err := encrypter.Encrypt(context.Background(), []byte("file content"), nil)
+	assert.Nil(t, err)
+
+	plaintext, err := encrypter.Decrypt(context.Background(), ciphertext, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "file content", string(plaintext))
+}
+
+//TestWrapKeyInvalid
+func TestWrapKeyInvalid(t *testing.T) {
+
+	funcEncrypt = func(input *kms.EncryptInput) (*kms.EncryptOutput, error) {
+		return nil, errors.New("access denied")
+	}
+
+	service, apiErr := NewServiceMock(keyID, region, KmsServiceMock{})
+	assert.Nil(t, apiErr)
+
+	wrapped, err := service.WrapKey(context.Background(), []byte("data-key"))
+
+	assert.Nil(t, wrapped)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "keyManagement: WrapKey : Unable to encrypt data key with alias/my-key, access denied", err.Error())
+}
+
+//TestUnwrapKeyInvalid
+func TestUnwrapKeyInvalid(t *testing.T) {
+
+	funcDecrypt = func(input *kms.DecryptInput) (*kms.DecryptOutput, error) {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	service, apiErr := NewServiceMock(keyID, region, KmsServiceMock{})
+	assert.Nil(t, apiErr)
+
+	plaintext, err := service.UnwrapKey(context.Background(), []byte("wrapped"))
+
+	assert.Nil(t, plaintext)
+	assert.NotNil(t, err)
+}
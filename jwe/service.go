@@ -3,6 +3,7 @@ package jwe
 import (
 	"errors"
 	"fmt"
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"github.com/lelinu/api_utils/utils/error_utils"
 	"gopkg.in/square/go-jose.v2"
 	"gopkg.in/square/go-jose.v2/jwt"
@@ -12,7 +13,7 @@ import (
 
 //Service struct
 type Service struct {
-	timeFunc            func() time.Time
+	clock               date_utils.Clock
 	timeout             time.Duration
 	maxRefresh          time.Duration
 	encryptionAlgorithm string
@@ -67,7 +68,9 @@ func (a *Service) init(encryptionAlgorithm string, encryptionKey string, issuer
 	a.maxRefresh = time.Hour * maxRefreshInHours
 
 	// set defaults
-	a.timeFunc = time.Now
+	if a.clock == nil {
+		a.clock = date_utils.RealClock{}
+	}
 	if a.timeout <= 0 {
 		a.timeout = time.Hour * timeoutInHours
 	}
@@ -76,6 +79,11 @@ func (a *Service) init(encryptionAlgorithm string, encryptionKey string, issuer
 	return nil
 }
 
+//SetClock replaces the clock used for the token dates, meant for tests
+func (a *Service) SetClock(clock date_utils.Clock) {
+	a.clock = clock
+}
+
 //GenerateJweToken will generate a new jwe token
 func (a *Service) GenerateJweToken(customClaims map[string]interface{}) (string, *time.Time, *error_utils.ApiError) {
 
@@ -88,11 +96,11 @@ func (a *Service) GenerateJweToken(customClaims map[string]interface{}) (string,
 		return "", nil, error_utils.NewInternalServerError(err.Error())
 	}
 
-	expire := a.timeFunc().UTC().Add(a.timeout)
+	expire := a.clock.Now().UTC().Add(a.timeout)
 
 	claims := map[string]interface{} { }
 	claims["exp"] = expire.Unix()
-	claims["orig_iat"] = a.timeFunc().Unix()
+	claims["orig_iat"] = a.clock.Now().Unix()
 	claims["iss"] = a.issuer
 
 	if customClaims != nil {
@@ -131,9 +139,9 @@ func (a *Service) RefreshJweToken(token string) (string, *time.Time, *error_util
 		newClaims[key] = claims[key]
 	}
 
-	expire := a.timeFunc().UTC().Add(a.timeout)
+	expire := a.clock.Now().UTC().Add(a.timeout)
 	newClaims["exp"] = expire.Unix()
-	newClaims["orig_iat"] = a.timeFunc().Unix()
+	newClaims["orig_iat"] = a.clock.Now().Unix()
 
 	token, err = jwt.Encrypted(enc).Claims(newClaims).CompactSerialize()
 	if err != nil {
@@ -164,7 +172,7 @@ func (a *Service) ValidateJweToken(token string) (map[string]interface{}, *error
 
 	// get value and validate
 	origIat := int64(claims["orig_iat"].(float64))
-	if origIat < a.timeFunc().Add(-a.maxRefresh).Unix() {
+	if origIat < a.clock.Now().Add(-a.maxRefresh).Unix() {
 		return nil, error_utils.NewUnauthorizedError("Token is expired")
 	}
 
@@ -180,7 +188,7 @@ func (a *Service) ValidateJweToken(token string) (map[string]interface{}, *error
 
 	// get value and validate
 	exp := int64(claims["exp"].(float64))
-	if exp < a.timeFunc().Unix(){
+	if exp < a.clock.Now().Unix(){
 		return nil, error_utils.NewUnauthorizedError("Token is expired")
 	}
 	// validate dates
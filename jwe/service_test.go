@@ -1,6 +1,7 @@
 package jwe
 
 import (
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -362,3 +363,26 @@ func TestValidateEncryptionAlgorithm(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.EqualValues(t, "invalid encryption algorithm", err.Error())
 }
+
+//TestValidateJweTokenExpiresWithClock
+func TestValidateJweTokenExpiresWithClock(t *testing.T) {
+
+	// arrange
+	clock := date_utils.NewFakeClock(time.Now())
+	service, err := NewService(encryptionAlgorithm, encryptionKey, issuer, 1, 1)
+	assert.Nil(t, err)
+	service.SetClock(clock)
+	token, _, err := service.GenerateJweToken(customClaims)
+	assert.Nil(t, err)
+
+	// act
+	clock.Advance(59 * time.Minute)
+	_, validErr := service.ValidateJweToken(token)
+	clock.Advance(2 * time.Minute)
+	_, expiredErr := service.ValidateJweToken(token)
+
+	// assert
+	assert.Nil(t, validErr)
+	assert.NotNil(t, expiredErr)
+	assert.EqualValues(t, "Token is expired", expiredErr.ErrorMessage)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"github.com/lelinu/api_utils/utils/error_utils"
 
 	"github.com/golang-jwt/jwt"
@@ -13,6 +14,7 @@ import (
 
 //Service struct
 type Service struct {
+	clock            date_utils.Clock
 	timeout          time.Duration
 	maxRefresh       time.Duration
 	signingAlgorithm string
@@ -66,6 +68,9 @@ func (a *Service) init(signingAlgorithm string, jwtSecretKey string, issuer stri
 	a.maxRefresh = maxRefreshInMinutes
 
 	// set defaults
+	if a.clock == nil {
+		a.clock = date_utils.RealClock{}
+	}
 	if a.timeout <= 0 {
 		a.timeout = time.Minute * timeoutInMinutes
 	}
@@ -74,6 +79,11 @@ func (a *Service) init(signingAlgorithm string, jwtSecretKey string, issuer stri
 	return nil
 }
 
+//SetClock replaces the clock used for the token dates, meant for tests
+func (a *Service) SetClock(clock date_utils.Clock) {
+	a.clock = clock
+}
+
 // GenerateJwtToken will generate a new jwt token
 func (a *Service) GenerateJwtToken(customClaims map[string]interface{}) (string, *time.Time, *error_utils.ApiError) {
 
@@ -85,7 +95,7 @@ func (a *Service) GenerateJwtToken(customClaims map[string]interface{}) (string,
 	}
 
 	// get time now UTC
-	timeNowUTC := time.Now().UTC()
+	timeNowUTC := a.clock.Now().UTC()
 
 	expire := timeNowUTC.Add(a.timeout)
 	claims["exp"] = expire.Unix()
@@ -116,7 +126,7 @@ func (a *Service) RefreshJwtToken(token string) (string, *time.Time, *error_util
 	}
 
 	// get time now UTC
-	timeNowUTC := time.Now().UTC()
+	timeNowUTC := a.clock.Now().UTC()
 
 	expire := timeNowUTC.Add(a.timeout)
 	newClaims["exp"] = expire.Unix()
@@ -163,7 +173,7 @@ func (a *Service) ValidateJwtToken(token string) (map[string]interface{}, *error
 	exp := int64(claims["exp"].(float64))
 
 	// get time now UTC
-	timeNowUTC := time.Now().UTC()
+	timeNowUTC := a.clock.Now().UTC()
 
 	if exp < timeNowUTC.Unix() {
 		return nil, error_utils.NewUnauthorizedError("Token is expired")
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -259,3 +260,28 @@ func TestParseTokenStringInvalid(t *testing.T) {
 	assert.EqualValues(t, "token contains an invalid number of segments", err.Error())
 	assert.Nil(t, jwtToken)
 }
+
+//TestValidateJwtTokenExpiresWithClock
+func TestValidateJwtTokenExpiresWithClock(t *testing.T) {
+
+	// arrange
+	start := time.Now().UTC()
+	clock := date_utils.NewFakeClock(start)
+	service, err := NewService(signingAlgorithm, jwtSecretKey, issuer, 1, 1)
+	assert.Nil(t, err)
+	service.SetClock(clock)
+	token, expire, err := service.GenerateJwtToken(customClaims)
+	assert.Nil(t, err)
+
+	// act
+	clock.Advance(59 * time.Second)
+	_, validErr := service.ValidateJwtToken(token)
+	clock.Advance(2 * time.Second)
+	_, expiredErr := service.ValidateJwtToken(token)
+
+	// assert
+	assert.EqualValues(t, start.Add(time.Minute), *expire)
+	assert.Nil(t, validErr)
+	assert.NotNil(t, expiredErr)
+	assert.EqualValues(t, "Token is expired", expiredErr.ErrorMessage)
+}
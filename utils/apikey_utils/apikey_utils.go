@@ -0,0 +1,209 @@
+package apikey_utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/random_utils"
+)
+
+const (
+	DefaultPrefixLength = 12
+	DefaultSecretLength = 40
+
+	// lastUsedResolution last used timestamps are only written when older, to avoid a write on every request
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrInvalidKey        = errors.New("apikey: invalid api key")
+	ErrKeyExpired        = errors.New("apikey: api key has expired")
+	ErrKeyRevoked        = errors.New("apikey: api key has been revoked")
+	ErrInsufficientScope = errors.New("apikey: api key is missing a required scope")
+	ErrKeyNotFound       = errors.New("apikey: api key not found")
+	ErrInvalidLabel      = errors.New("apikey: label must only contain letters, digits and dashes")
+
+	// same shape as validator_utils IsValid2SegmentAPIKey
+	keyFormat   = regexp.MustCompile(`^([a-zA-Z0-9-]+)\.([a-zA-Z0-9-]+)$`)
+	labelFormat = regexp.MustCompile(`^[a-zA-Z0-9-]*$`)
+
+	// compared against when the prefix is unknown, so that unknown and wrong keys take the same time
+	dummySecretHash = HashSecret("")
+)
+
+//APIKey stored api key, the secret itself is never stored
+type APIKey struct {
+	ID         uint64     `gorm:"primary_key" json:"id"`
+	Prefix     string     `gorm:"type:varchar(64);unique_index;not null" json:"prefix"`
+	SecretHash string     `gorm:"type:char(64);not null" json:"-"`
+	Name       string     `gorm:"type:varchar(255)" json:"name"`
+	OwnerID    string     `gorm:"type:varchar(64);index" json:"owner_id"`
+	Scopes     string     `gorm:"type:text" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+//TableName gorm table name
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+//ScopeList returns the scopes granted to the key
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, " ")
+}
+
+//HasScope checks if the key was granted the scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.ScopeList() {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+//IsExpired checks if the key has expired at the given time
+func (k *APIKey) IsExpired(at time.Time) bool {
+	return k.ExpiresAt != nil && !at.Before(*k.ExpiresAt)
+}
+
+//CreateOptions options of a new api key
+type CreateOptions struct {
+	// Label optional readable start of the prefix, such as live or test
+	Label   string
+	Name    string
+	OwnerID string
+	Scopes  []string
+	// ExpiresAt optional expiry, keys never expire when nil
+	ExpiresAt *time.Time
+}
+
+//Manager mints and verifies api keys shaped prefix.secret, where the prefix is a public id used to look the key up
+type Manager struct {
+	store Store
+	clock date_utils.Clock
+}
+
+//NewManager constructor
+func NewManager(store Store) *Manager {
+	return &Manager{
+		store: store,
+		clock: date_utils.RealClock{},
+	}
+}
+
+//SetClock replaces the clock used for the creation, expiry, last use and revocation dates
+func (m *Manager) SetClock(clock date_utils.Clock) {
+	m.clock = clock
+}
+
+//Create mints and stores a new key, returning the full key to show once to the user. Random source failures are
+//returned rather than producing a predictable key.
+func (m *Manager) Create(ctx context.Context, options CreateOptions) (string, *APIKey, error) {
+	if !labelFormat.MatchString(options.Label) {
+		return "", nil, ErrInvalidLabel
+	}
+
+	prefix, err := random_utils.NewRandomStringWithAlphabet(random_utils.AlphanumericAlphabet, DefaultPrefixLength)
+	if err != nil {
+		return "", nil, err
+	}
+	if options.Label != "" {
+		prefix = options.Label + "-" + prefix
+	}
+	secret, err := random_utils.NewRandomStringWithAlphabet(random_utils.AlphanumericAlphabet, DefaultSecretLength)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &APIKey{
+		Prefix:     prefix,
+		SecretHash: HashSecret(secret),
+		Name:       options.Name,
+		OwnerID:    options.OwnerID,
+		Scopes:     strings.Join(options.Scopes, " "),
+		ExpiresAt:  options.ExpiresAt,
+		CreatedAt:  m.clock.Now(),
+	}
+	if err := m.store.Create(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	return prefix + "." + secret, key, nil
+}
+
+//Verify checks the presented key and that it was granted every required scope, updating its last used timestamp
+func (m *Manager) Verify(ctx context.Context, presented string, requiredScopes ...string) (*APIKey, error) {
+	prefix, secret, err := Parse(presented)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := m.store.FindByPrefix(ctx, prefix)
+	if err == ErrKeyNotFound {
+		subtle.ConstantTimeCompare([]byte(dummySecretHash), []byte(HashSecret(secret)))
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(HashSecret(secret))) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := m.clock.Now()
+	if key.RevokedAt != nil {
+		return nil, ErrKeyRevoked
+	}
+	if key.IsExpired(now) {
+		return nil, ErrKeyExpired
+	}
+	for _, scope := range requiredScopes {
+		if !key.HasScope(scope) {
+			return nil, ErrInsufficientScope
+		}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := m.store.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+//Revoke revokes the key with the prefix, it fails verification from then on
+func (m *Manager) Revoke(ctx context.Context, prefix string) error {
+	return m.store.Revoke(ctx, prefix, m.clock.Now())
+}
+
+//Parse splits a key into its prefix and secret
+func Parse(key string) (string, string, error) {
+	matches := keyFormat.FindStringSubmatch(strings.TrimSpace(key))
+	if matches == nil {
+		return "", "", ErrInvalidKey
+	}
+	return matches[1], matches[2], nil
+}
+
+//HashSecret returns the hex encoded SHA-256 of the secret. A fast hash is enough as secrets are long and random.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
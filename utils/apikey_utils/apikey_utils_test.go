@@ -0,0 +1,195 @@
+package apikey_utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/validator_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+//memoryStore in memory store for the tests
+type memoryStore struct {
+	keys    map[string]*APIKey
+	touches int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: map[string]*APIKey{}}
+}
+
+func (s *memoryStore) Create(ctx context.Context, key *APIKey) error {
+	key.ID = uint64(len(s.keys) + 1)
+	stored := *key
+	s.keys[key.Prefix] = &stored
+	return nil
+}
+
+func (s *memoryStore) FindByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	key, ok := s.keys[prefix]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	found := *key
+	return &found, nil
+}
+
+func (s *memoryStore) TouchLastUsed(ctx context.Context, id uint64, at time.Time) error {
+	s.touches++
+	for _, key := range s.keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Revoke(ctx context.Context, prefix string, at time.Time) error {
+	key, ok := s.keys[prefix]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	key.RevokedAt = &at
+	return nil
+}
+
+func TestCreateSuccessful(t *testing.T) {
+	// arrange
+	store := newMemoryStore()
+	manager := NewManager(store)
+
+	// act
+	plaintext, key, err := manager.Create(context.Background(), CreateOptions{Label: "live", Name: "ci", Scopes: []string{"read", "write"}})
+	prefix, secret, parseErr := Parse(plaintext)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, parseErr)
+	assert.Equal(t, key.Prefix, prefix)
+	assert.Regexp(t, "^live-[a-zA-Z0-9]{12}$", prefix)
+	assert.EqualValues(t, DefaultSecretLength, len(secret))
+	assert.Equal(t, HashSecret(secret), store.keys[prefix].SecretHash)
+	assert.NotContains(t, store.keys[prefix].SecretHash, secret)
+	assert.Equal(t, []string{"read", "write"}, key.ScopeList())
+}
+
+func TestCreateMatchesValidator(t *testing.T) {
+	// arrange
+	manager := NewManager(newMemoryStore())
+	plaintext, _, _ := manager.Create(context.Background(), CreateOptions{Label: "test"})
+
+	// act
+	validator := validator_utils.NewValidator()
+	valid := validator.IsValid2SegmentAPIKey("ApiKey", plaintext)
+
+	// assert
+	assert.True(t, valid)
+}
+
+func TestCreateInvalidLabel(t *testing.T) {
+	// act
+	_, _, err := NewManager(newMemoryStore()).Create(context.Background(), CreateOptions{Label: "live.key"})
+
+	// assert
+	assert.Equal(t, ErrInvalidLabel, err)
+}
+
+func TestVerifySuccessful(t *testing.T) {
+	// arrange
+	store := newMemoryStore()
+	manager := NewManager(store)
+	plaintext, _, _ := manager.Create(context.Background(), CreateOptions{Scopes: []string{"read"}})
+
+	// act
+	key, err := manager.Verify(context.Background(), plaintext, "read")
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, key.LastUsedAt)
+	assert.EqualValues(t, 1, store.touches)
+}
+
+func TestVerifyLastUsedResolution(t *testing.T) {
+	// arrange
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore()
+	manager := NewManager(store)
+	clock := date_utils.NewFakeClock(now)
+	manager.SetClock(clock)
+	plaintext, _, _ := manager.Create(context.Background(), CreateOptions{})
+
+	// act
+	_, _ = manager.Verify(context.Background(), plaintext)
+	clock.Advance(30 * time.Second)
+	_, _ = manager.Verify(context.Background(), plaintext)
+	clock.Advance(time.Minute)
+	_, _ = manager.Verify(context.Background(), plaintext)
+
+	// assert
+	assert.EqualValues(t, 2, store.touches)
+}
+
+func TestVerifyInvalidKeys(t *testing.T) {
+	// arrange
+	manager := NewManager(newMemoryStore())
+	plaintext, key, _ := manager.Create(context.Background(), CreateOptions{})
+
+	// act
+	_, malformedErr := manager.Verify(context.Background(), "not-a-key")
+	_, unknownErr := manager.Verify(context.Background(), "unknown.secret")
+	_, wrongSecretErr := manager.Verify(context.Background(), key.Prefix+".wrongsecret")
+	_, validErr := manager.Verify(context.Background(), plaintext)
+
+	// assert
+	assert.Equal(t, ErrInvalidKey, malformedErr)
+	assert.Equal(t, ErrInvalidKey, unknownErr)
+	assert.Equal(t, ErrInvalidKey, wrongSecretErr)
+	assert.Nil(t, validErr)
+}
+
+func TestVerifyExpiredKey(t *testing.T) {
+	// arrange
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	manager := NewManager(newMemoryStore())
+	clock := date_utils.NewFakeClock(now)
+	manager.SetClock(clock)
+	plaintext, _, _ := manager.Create(context.Background(), CreateOptions{ExpiresAt: &expiresAt})
+
+	// act
+	_, beforeErr := manager.Verify(context.Background(), plaintext)
+	clock.Set(expiresAt)
+	_, afterErr := manager.Verify(context.Background(), plaintext)
+
+	// assert
+	assert.Nil(t, beforeErr)
+	assert.Equal(t, ErrKeyExpired, afterErr)
+}
+
+func TestVerifyRevokedKey(t *testing.T) {
+	// arrange
+	manager := NewManager(newMemoryStore())
+	plaintext, key, _ := manager.Create(context.Background(), CreateOptions{})
+
+	// act
+	revokeErr := manager.Revoke(context.Background(), key.Prefix)
+	_, err := manager.Verify(context.Background(), plaintext)
+
+	// assert
+	assert.Nil(t, revokeErr)
+	assert.Equal(t, ErrKeyRevoked, err)
+}
+
+func TestVerifyMissingScope(t *testing.T) {
+	// arrange
+	manager := NewManager(newMemoryStore())
+	plaintext, _, _ := manager.Create(context.Background(), CreateOptions{Scopes: []string{"read"}})
+
+	// act
+	_, err := manager.Verify(context.Background(), plaintext, "read", "write")
+
+	// assert
+	assert.Equal(t, ErrInsufficientScope, err)
+}
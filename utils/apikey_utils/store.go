@@ -0,0 +1,76 @@
+package apikey_utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Store persists api keys
+type Store interface {
+	Create(ctx context.Context, key *APIKey) error
+	// FindByPrefix returns ErrKeyNotFound when no key has the prefix
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	TouchLastUsed(ctx context.Context, id uint64, at time.Time) error
+	// Revoke returns ErrKeyNotFound when no active key has the prefix
+	Revoke(ctx context.Context, prefix string, at time.Time) error
+}
+
+//GormStore gorm backed store of the api_keys table
+type GormStore struct {
+	db *gorm.DB
+}
+
+//NewGormStore constructor, run AutoMigrate(&APIKey{}) or an equivalent migration beforehand
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+//Create inserts the key
+func (s *GormStore) Create(ctx context.Context, key *APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.Create(key).Error
+}
+
+//FindByPrefix returns the key with the prefix
+func (s *GormStore) FindByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var key APIKey
+	if err := s.db.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+//TouchLastUsed sets the last used timestamp
+func (s *GormStore) TouchLastUsed(ctx context.Context, id uint64, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.Model(&APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+//Revoke sets the revoked timestamp of an active key
+func (s *GormStore) Revoke(ctx context.Context, prefix string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	result := s.db.Model(&APIKey{}).Where("prefix = ? AND revoked_at IS NULL", prefix).UpdateColumn("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package apikey_utils
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockStore(t *testing.T) (*GormStore, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	db, err := gorm.Open("mysql", sqlDB)
+	assert.Nil(t, err)
+
+	return NewGormStore(db), mock
+}
+
+func TestGormStoreFindByPrefixSuccessful(t *testing.T) {
+	// arrange
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `api_keys` WHERE (prefix = ?)")).
+		WithArgs("live-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "prefix", "secret_hash", "scopes"}).AddRow(1, "live-abc", "hash", "read"))
+
+	// act
+	key, err := store.FindByPrefix(context.Background(), "live-abc")
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, key.ID)
+	assert.Equal(t, "hash", key.SecretHash)
+	assert.True(t, key.HasScope("read"))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGormStoreFindByPrefixNotFound(t *testing.T) {
+	// arrange
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `api_keys` WHERE (prefix = ?)")).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// act
+	key, err := store.FindByPrefix(context.Background(), "missing")
+
+	// assert
+	assert.Nil(t, key)
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestGormStoreTouchLastUsedSuccessful(t *testing.T) {
+	// arrange
+	store, mock := newMockStore(t)
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `api_keys` SET `last_used_at` = ? WHERE (id = ?)")).
+		WithArgs(at, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// act
+	err := store.TouchLastUsed(context.Background(), 7, at)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGormStoreRevokeNotFound(t *testing.T) {
+	// arrange
+	store, mock := newMockStore(t)
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `api_keys` SET `revoked_at` = ? WHERE (prefix = ? AND revoked_at IS NULL)")).
+		WithArgs(at, "live-abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// act
+	err := store.Revoke(context.Background(), "live-abc", at)
+
+	// assert
+	assert.Equal(t, ErrKeyNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGormStoreCanceledContext(t *testing.T) {
+	// arrange
+	store, _ := newMockStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	_, err := store.FindByPrefix(ctx, "live-abc")
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,87 @@
+package base64_utils
+
+import (
+	"fmt"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Values = base58DecodingTable()
+
+//encodeBase58 encodes the bytes as a big endian number in base 58, each leading zero byte becoming a 1
+func encodeBase58(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) is about 1.37
+	digits := make([]byte, 0, len(data)*138/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, digit := range digits {
+		out[len(out)-1-i] = base58Alphabet[digit]
+	}
+	return string(out)
+}
+
+//decodeBase58 decodes a string produced by encodeBase58
+func decodeBase58(str string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(str) && str[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) is about 0.733
+	bytes := make([]byte, 0, len(str)*733/1000+1)
+	for i := zeros; i < len(str); i++ {
+		value := base58Values[str[i]]
+		if value == 0xff {
+			return nil, fmt.Errorf("base58: illegal character %q at position %d", str[i], i)
+		}
+
+		carry := int(value)
+		for j := range bytes {
+			carry += int(bytes[j]) * 58
+			bytes[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			bytes = append(bytes, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(bytes))
+	for i, b := range bytes {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+//base58DecodingTable maps characters to their value, 0xff for invalid ones
+func base58DecodingTable() [256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = 0xff
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		table[base58Alphabet[i]] = byte(i)
+	}
+	return table
+}
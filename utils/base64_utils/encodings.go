@@ -0,0 +1,172 @@
+package base64_utils
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//Encoding binary to text encoding
+type Encoding int
+
+const (
+	// Std standard padded base64, as used by the other functions of this package
+	Std Encoding = iota
+	// URL url safe padded base64
+	URL
+	// RawStd standard base64 without padding
+	RawStd
+	// RawURL url safe base64 without padding, as used by JWT segments
+	RawURL
+	// Base32 standard padded base32
+	Base32
+	// Base58 bitcoin alphabet base58, without the 0, O, I and l characters that are easy to confuse
+	Base58
+	// Hex lower case hexadecimal
+	Hex
+)
+
+var ErrStreamingNotSupported = errors.New("base64: streaming is not supported by base58")
+
+//String returns the name of the encoding
+func (e Encoding) String() string {
+	switch e {
+	case Std:
+		return "base64"
+	case URL:
+		return "base64url"
+	case RawStd:
+		return "base64 unpadded"
+	case RawURL:
+		return "base64url unpadded"
+	case Base32:
+		return "base32"
+	case Base58:
+		return "base58"
+	case Hex:
+		return "hex"
+	}
+	return fmt.Sprintf("encoding(%d)", int(e))
+}
+
+//base64Encoding returns the standard library encoding of the base64 variants
+func (e Encoding) base64Encoding() *base64.Encoding {
+	switch e {
+	case Std:
+		return base64.StdEncoding
+	case URL:
+		return base64.URLEncoding
+	case RawStd:
+		return base64.RawStdEncoding
+	case RawURL:
+		return base64.RawURLEncoding
+	}
+	return nil
+}
+
+//Encode encodes the bytes with the encoding
+func Encode(data []byte, encoding Encoding) (string, error) {
+	if enc := encoding.base64Encoding(); enc != nil {
+		return enc.EncodeToString(data), nil
+	}
+
+	switch encoding {
+	case Base32:
+		return base32.StdEncoding.EncodeToString(data), nil
+	case Base58:
+		return encodeBase58(data), nil
+	case Hex:
+		return hex.EncodeToString(data), nil
+	}
+	return "", fmt.Errorf("base64: unknown %v", encoding)
+}
+
+//Decode decodes the string with the encoding
+func Decode(str string, encoding Encoding) ([]byte, error) {
+	if enc := encoding.base64Encoding(); enc != nil {
+		return enc.DecodeString(str)
+	}
+
+	switch encoding {
+	case Base32:
+		return base32.StdEncoding.DecodeString(strings.ToUpper(str))
+	case Base58:
+		return decodeBase58(str)
+	case Hex:
+		return hex.DecodeString(str)
+	}
+	return nil, fmt.Errorf("base64: unknown %v", encoding)
+}
+
+//DetectBase64Encoding returns the base64 variant of the string, from its alphabet and padding
+func DetectBase64Encoding(str string) Encoding {
+	urlSafe := strings.ContainsAny(str, "-_")
+	padded := strings.HasSuffix(str, "=")
+
+	// without - and _ both alphabets agree, so a string without them decodes the same either way
+	switch {
+	case urlSafe && padded:
+		return URL
+	case urlSafe:
+		return RawURL
+	case padded || len(str)%4 == 0:
+		return Std
+	}
+	return RawStd
+}
+
+//DecodeAuto decodes standard or url safe base64, with or without padding, such as values received from clients
+//that do not agree on a variant
+func DecodeAuto(str string) ([]byte, error) {
+	str = strings.TrimSpace(str)
+	return Decode(str, DetectBase64Encoding(str))
+}
+
+//NewEncoder returns a writer encoding everything written to w, for large files that should not be held in memory.
+//Close must be called to flush the last partial block.
+func NewEncoder(encoding Encoding, w io.Writer) (io.WriteCloser, error) {
+	if enc := encoding.base64Encoding(); enc != nil {
+		return base64.NewEncoder(enc, w), nil
+	}
+
+	switch encoding {
+	case Base32:
+		return base32.NewEncoder(base32.StdEncoding, w), nil
+	case Hex:
+		return nopWriteCloser{hex.NewEncoder(w)}, nil
+	case Base58:
+		return nil, ErrStreamingNotSupported
+	}
+	return nil, fmt.Errorf("base64: unknown %v", encoding)
+}
+
+//NewDecoder returns a reader decoding the content of r
+func NewDecoder(encoding Encoding, r io.Reader) (io.Reader, error) {
+	if enc := encoding.base64Encoding(); enc != nil {
+		return base64.NewDecoder(enc, r), nil
+	}
+
+	switch encoding {
+	case Base32:
+		return base32.NewDecoder(base32.StdEncoding, r), nil
+	case Hex:
+		return hex.NewDecoder(r), nil
+	case Base58:
+		return nil, ErrStreamingNotSupported
+	}
+	return nil, fmt.Errorf("base64: unknown %v", encoding)
+}
+
+//nopWriteCloser adds a no-op Close to encoders that do not buffer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+//Close does nothing
+func (nopWriteCloser) Close() error {
+	return nil
+}
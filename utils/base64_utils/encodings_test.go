@@ -0,0 +1,111 @@
+package base64_utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeAllEncodings(t *testing.T) {
+	//arrange
+	input := []byte{0xfb, 0xff, 0xbf, 0x01}
+	expected := map[Encoding]string{
+		Std:    "+/+/AQ==",
+		URL:    "-_-_AQ==",
+		RawStd: "+/+/AQ",
+		RawURL: "-_-_AQ",
+		Base32: "7P736AI=",
+		Base58: "7Sbo52",
+		Hex:    "fbffbf01",
+	}
+
+	for encoding, value := range expected {
+		//act
+		encoded, err := Encode(input, encoding)
+		decoded, decodeErr := Decode(value, encoding)
+
+		//assert
+		assert.Nil(t, err)
+		assert.Nil(t, decodeErr)
+		assert.Equal(t, value, encoded, encoding.String())
+		assert.Equal(t, input, decoded, encoding.String())
+	}
+}
+
+func TestBase58KnownValues(t *testing.T) {
+	//act
+	hello, _ := Encode([]byte("Hello World!"), Base58)
+	leadingZeros, _ := Encode([]byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, Base58)
+	decoded, err := Decode("11233QC4", Base58)
+	_, invalidErr := Decode("0OIl", Base58)
+
+	//assert
+	assert.Equal(t, "2NEpo7TZRRrLZSi2U", hello)
+	assert.Equal(t, "11233QC4", leadingZeros)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, decoded)
+	assert.NotNil(t, invalidErr)
+}
+
+func TestDecodeAutoSuccessful(t *testing.T) {
+	//arrange
+	expected := []byte{0xfb, 0xff, 0xbf, 0x01}
+
+	for _, value := range []string{"+/+/AQ==", "-_-_AQ==", "+/+/AQ", "-_-_AQ", " +/+/AQ==\n"} {
+		//act
+		decoded, err := DecodeAuto(value)
+
+		//assert
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, decoded, value)
+	}
+}
+
+func TestDecodeAutoJWTSegment(t *testing.T) {
+	//act
+	decoded, err := DecodeAuto("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9")
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, `{"alg":"HS256","typ":"JWT"}`, string(decoded))
+}
+
+func TestStreamingRoundTrip(t *testing.T) {
+	//arrange
+	input := bytes.Repeat([]byte("large file content "), 1000)
+
+	for _, encoding := range []Encoding{Std, URL, RawStd, RawURL, Base32, Hex} {
+		var encoded bytes.Buffer
+
+		//act
+		encoder, err := NewEncoder(encoding, &encoded)
+		assert.Nil(t, err)
+		_, writeErr := encoder.Write(input)
+		closeErr := encoder.Close()
+		expected, _ := Encode(input, encoding)
+		actual := encoded.String()
+
+		decoder, decoderErr := NewDecoder(encoding, &encoded)
+		assert.Nil(t, decoderErr)
+		decoded, readErr := ioutil.ReadAll(decoder)
+
+		//assert
+		assert.Nil(t, writeErr)
+		assert.Nil(t, closeErr)
+		assert.Nil(t, readErr)
+		assert.Equal(t, input, decoded, encoding.String())
+		assert.Equal(t, expected, actual, encoding.String())
+	}
+}
+
+func TestStreamingBase58NotSupported(t *testing.T) {
+	//act
+	_, encoderErr := NewEncoder(Base58, &bytes.Buffer{})
+	_, decoderErr := NewDecoder(Base58, &bytes.Buffer{})
+
+	//assert
+	assert.Equal(t, ErrStreamingNotSupported, encoderErr)
+	assert.Equal(t, ErrStreamingNotSupported, decoderErr)
+}
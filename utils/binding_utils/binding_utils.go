@@ -0,0 +1,313 @@
+package binding_utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/error_utils"
+	"github.com/lelinu/api_utils/utils/validator_utils"
+)
+
+const (
+	PathTag    = "path"
+	QueryTag   = "query"
+	FormTag    = "form"
+	DefaultTag = "default"
+	LayoutTag  = "layout"
+
+	defaultMaxMemory = 32 << 20
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// layouts tried in order when a time field has no layout tag
+	defaultTimeLayouts = []string{date_utils.ApiDateTimeFormat, date_utils.DbDateTimeFormat, "2006-01-02"}
+)
+
+//Validatable implemented by bound structs that validate themselves once every parameter has been decoded
+type Validatable interface {
+	Validate(v *validator_utils.Validator)
+}
+
+//BindRequest decodes path parameters, query parameters and form values of r into out, a pointer to a struct,
+//using the path, query and form field tags. Fields can carry a default tag, a layout tag for time.Time,
+//and a ",required" tag option. If out implements Validatable it is validated afterwards.
+//Every bad parameter is reported in the returned bad request error.
+func BindRequest(r *http.Request, pathParams map[string]string, out interface{}) *error_utils.ApiError {
+	sources := map[string]url.Values{
+		QueryTag: r.URL.Query(),
+		PathTag:  url.Values{},
+	}
+
+	for key, value := range pathParams {
+		sources[PathTag].Set(key, value)
+	}
+
+	if r.Body != nil && r.Method != http.MethodGet {
+		var err error
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			err = r.ParseMultipartForm(defaultMaxMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			return error_utils.NewBadRequestError(fmt.Sprintf("Binding: unable to parse form, %v", err))
+		}
+	}
+	sources[FormTag] = r.PostForm
+
+	return bind(sources, out)
+}
+
+//BindQuery decodes query parameters into out using the query field tags, then validates it if out implements Validatable
+func BindQuery(values url.Values, out interface{}) *error_utils.ApiError {
+	return bind(map[string]url.Values{QueryTag: values}, out)
+}
+
+//BindForm decodes form values into out using the form field tags, then validates it if out implements Validatable
+func BindForm(values url.Values, out interface{}) *error_utils.ApiError {
+	return bind(map[string]url.Values{FormTag: values}, out)
+}
+
+//bind decodes every source into out and aggregates decoding and validation errors. Validation runs on the partially
+//bound struct too, its error is only dropped when it names a parameter that already failed to decode
+func bind(sources map[string]url.Values, out interface{}) *error_utils.ApiError {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return error_utils.NewInternalServerError("Binding: out must be a non nil pointer to a struct")
+	}
+
+	var errs []string
+	bindStruct(sources, rv.Elem(), &errs)
+
+	if validatable, ok := out.(Validatable); ok {
+		validator := validator_utils.NewValidator()
+		validatable.Validate(validator)
+		if !validator.IsValid() && !isReported(errs, validator.Error().Error()) {
+			errs = append(errs, validator.Error().Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return error_utils.NewBadRequestError(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+//isReported checks if errs already holds an error for the parameter named by message, formatted as "name - reason"
+func isReported(errs []string, message string) bool {
+	name, _, ok := strings.Cut(message, " - ")
+	if !ok {
+		return false
+	}
+	for _, err := range errs {
+		if strings.HasPrefix(err, name+" - ") {
+			return true
+		}
+	}
+	return false
+}
+
+//bindStruct decodes every tagged field of the struct, descending into embedded and untagged nested structs.
+//Nil pointers to nested structs are only allocated when one of their parameters is present, so that optional groups
+//stay nil and their required parameters are not reported. Returns whether any parameter was present, defaults aside.
+func bindStruct(sources map[string]url.Values, rv reflect.Value, errs *[]string) bool {
+	present := false
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := rv.Field(i)
+
+		name, required, values, tagged := lookupField(sources, field)
+		if !tagged {
+			switch {
+			case fv.Kind() == reflect.Struct && field.Type != timeType:
+				present = bindStruct(sources, fv, errs) || present
+			case fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct && fv.Type().Elem() != timeType:
+				if !fv.IsNil() {
+					present = bindStruct(sources, fv.Elem(), errs) || present
+					continue
+				}
+				// bound aside, and kept with its errors only when one of its parameters is present
+				nested := reflect.New(fv.Type().Elem())
+				var nestedErrs []string
+				if bindStruct(sources, nested.Elem(), &nestedErrs) {
+					fv.Set(nested)
+					*errs = append(*errs, nestedErrs...)
+					present = true
+				}
+			}
+			continue
+		}
+
+		if len(values) > 0 {
+			present = true
+		} else if def, ok := field.Tag.Lookup(DefaultTag); ok {
+			values = []string{def}
+		}
+
+		if len(values) == 0 {
+			if required {
+				*errs = append(*errs, fmt.Sprintf("%s - Value is required", name))
+			}
+			continue
+		}
+
+		if err := setField(fv, values, field.Tag.Get(LayoutTag)); err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s - %s", name, err.Error()))
+		}
+	}
+	return present
+}
+
+//lookupField returns the parameter name, required flag and values of the first source tag found on the field
+func lookupField(sources map[string]url.Values, field reflect.StructField) (string, bool, []string, bool) {
+	for _, tag := range []string{PathTag, QueryTag, FormTag} {
+		tagValue, ok := field.Tag.Lookup(tag)
+		if !ok || tagValue == "-" {
+			continue
+		}
+
+		parts := strings.Split(tagValue, ",")
+		name := parts[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		required := false
+		for _, option := range parts[1:] {
+			if option == "required" {
+				required = true
+			}
+		}
+
+		var values []string
+		for _, value := range sources[tag][name] {
+			if strings.TrimSpace(value) != "" {
+				values = append(values, value)
+			}
+		}
+		return name, required, values, true
+	}
+	return "", false, nil, false
+}
+
+//setField converts the values to the field type
+func setField(fv reflect.Value, values []string, layout string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(fv.Type().Elem())
+		if err := setField(elem.Elem(), values, layout); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+
+	case reflect.Slice:
+		// accept both repeated parameters and comma separated lists
+		var items []string
+		for _, value := range values {
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		slice := reflect.MakeSlice(fv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item, layout); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setValue(fv, values[0], layout)
+}
+
+//setValue converts a single value to the field type
+func setValue(fv reflect.Value, value string, layout string) error {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case fv.Type() == timeType:
+		t, err := parseTime(value, layout)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("Value must be a duration such as 1h30m")
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Value must be a boolean")
+		}
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Value must be an integer")
+		}
+		fv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Value must be a positive integer")
+		}
+		fv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("Value must be numeric")
+		}
+		fv.SetFloat(n)
+
+	default:
+		return fmt.Errorf("Value has an unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+//parseTime parses the value with the given layout, or the date_utils layouts when layout is empty
+func parseTime(value string, layout string) (time.Time, error) {
+	layouts := defaultTimeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Value must be a date in format %s", strings.Join(layouts, " or "))
+}
@@ -0,0 +1,194 @@
+package binding_utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/validator_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type Paging struct {
+	Page    int64 `query:"page" default:"1"`
+	PerPage int64 `query:"per_page" default:"20"`
+}
+
+type listOrdersRequest struct {
+	Paging
+	MerchantID string        `path:"merchant_id,required"`
+	Status     []string      `query:"status"`
+	IDs        []int         `query:"ids"`
+	Active     *bool         `query:"active"`
+	From       time.Time     `query:"from"`
+	Day        time.Time     `query:"day" layout:"2006-01-02"`
+	Timeout    time.Duration `query:"timeout"`
+	Amount     float64       `query:"amount"`
+	Note       string        `form:"note"`
+	ignored    string
+}
+
+func (r *listOrdersRequest) Validate(v *validator_utils.Validator) {
+	v.ContainsList("status", r.Status, []string{"open", "closed"}, true)
+	validator_utils.Max(v, "per_page", r.PerPage, 100)
+}
+
+func TestBindRequestSuccessful(t *testing.T) {
+	// arrange
+	query := "status=open&status=closed&ids=1,2,3&active=true&from=2021-05-01T10:00:00Z&day=2021-05-02&timeout=1m30s&amount=10.5"
+	r := httptest.NewRequest(http.MethodPost, "/merchants/m1/orders?"+query, strings.NewReader("note=hello"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var request listOrdersRequest
+
+	// act
+	err := BindRequest(r, map[string]string{"merchant_id": "m1"}, &request)
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, request.Page)
+	assert.EqualValues(t, 20, request.PerPage)
+	assert.EqualValues(t, "m1", request.MerchantID)
+	assert.EqualValues(t, []string{"open", "closed"}, request.Status)
+	assert.EqualValues(t, []int{1, 2, 3}, request.IDs)
+	assert.NotNil(t, request.Active)
+	assert.EqualValues(t, true, *request.Active)
+	assert.EqualValues(t, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC), request.From.UTC())
+	assert.EqualValues(t, time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC), request.Day)
+	assert.EqualValues(t, 90*time.Second, request.Timeout)
+	assert.EqualValues(t, 10.5, request.Amount)
+	assert.EqualValues(t, "hello", request.Note)
+}
+
+func TestBindRequestAggregatesErrorsSuccessful(t *testing.T) {
+	// arrange
+	r := httptest.NewRequest(http.MethodGet, "/orders?page=abc&active=maybe&from=yesterday&ids=1,x", nil)
+	var request listOrdersRequest
+	expectedErr := "page - Value must be an integer; " +
+		"merchant_id - Value is required; " +
+		"ids - Value must be an integer; " +
+		"active - Value must be a boolean; " +
+		"from - Value must be a date in format 2006-01-02T15:04:05Z07:00 or 2006-01-02 15:04:05 or 2006-01-02"
+
+	// act
+	err := BindRequest(r, nil, &request)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.HttpStatusCode)
+	assert.EqualValues(t, expectedErr, err.ErrorMessage)
+}
+
+func TestBindRequestValidatesDecodedFieldsSuccessful(t *testing.T) {
+	// arrange
+	r := httptest.NewRequest(http.MethodGet, "/orders?ids=1,x&status=pending", nil)
+	var request listOrdersRequest
+	expectedErr := "ids - Value must be an integer; status - Value is not in the allowed list: open,closed"
+
+	// act
+	err := BindRequest(r, map[string]string{"merchant_id": "m1"}, &request)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, expectedErr, err.ErrorMessage)
+}
+
+type period struct {
+	From time.Time `query:"from,required" layout:"2006-01-02"`
+	To   time.Time `query:"to" layout:"2006-01-02"`
+}
+
+type reportRequest struct {
+	Limit  int `query:"limit"`
+	Period *period
+}
+
+func (r *reportRequest) Validate(v *validator_utils.Validator) {
+	validator_utils.Min(v, "limit", r.Limit, 1)
+}
+
+func TestBindQueryNestedPointerSuccessful(t *testing.T) {
+	// arrange
+	values := url.Values{"limit": {"5"}, "to": {"2021-05-02"}}
+	var request reportRequest
+	var optional reportRequest
+
+	// act
+	err := BindQuery(values, &request)
+	optionalErr := BindQuery(url.Values{"limit": {"5"}}, &optional)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "from - Value is required", err.ErrorMessage)
+	assert.NotNil(t, request.Period)
+	assert.EqualValues(t, time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC), request.Period.To)
+	assert.Nil(t, optionalErr)
+	assert.Nil(t, optional.Period)
+}
+
+func TestBindQueryDoesNotReportDecodedParameterTwiceSuccessful(t *testing.T) {
+	// arrange
+	values := url.Values{"limit": {"many"}}
+	var request reportRequest
+
+	// act
+	err := BindQuery(values, &request)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "limit - Value must be an integer", err.ErrorMessage)
+}
+
+type searchRequest struct {
+	Paging
+	Status []string `query:"status"`
+}
+
+func (r *searchRequest) Validate(v *validator_utils.Validator) {
+	v.ContainsList("status", r.Status, []string{"open", "closed"}, true)
+}
+
+func TestBindQueryRunsValidationSuccessful(t *testing.T) {
+	// arrange
+	values := url.Values{"status": {"pending"}}
+	var request searchRequest
+	expectedErr := "status - Value is not in the allowed list: open,closed"
+
+	// act
+	err := BindQuery(values, &request)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, expectedErr, err.ErrorMessage)
+}
+
+func TestBindFormSuccessful(t *testing.T) {
+	// arrange
+	values := url.Values{"note": {"  hi  "}}
+	var request struct {
+		Note  string `form:"note"`
+		Count uint8  `form:"count" default:"3"`
+	}
+
+	// act
+	err := BindForm(values, &request)
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, "hi", request.Note)
+	assert.EqualValues(t, 3, request.Count)
+}
+
+func TestBindInvalidTargetSuccessful(t *testing.T) {
+	// arrange
+	var request listOrdersRequest
+
+	// act
+	err := BindQuery(url.Values{}, request)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.HttpStatusCode)
+}
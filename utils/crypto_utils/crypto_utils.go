@@ -1,11 +1,25 @@
 package crypto_utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"github.com/lelinu/api_utils/utils/random_utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// bcryptMaxInputLength bcrypt only uses the first 72 bytes of its input
+const bcryptMaxInputLength = 72
+
+//HashOptions options for the salted hash functions
+type HashOptions struct {
+	// Cost bcrypt cost, defaults to bcrypt.DefaultCost
+	Cost int
+	// Pepper optional server side secret mixed into every hash, must be kept outside of the database
+	Pepper []byte
+}
+
 func GenerateHashFromString(input string) (string, error) {
 
 	hashedInput, err := bcrypt.GenerateFromPassword([]byte(input), bcrypt.DefaultCost)
@@ -25,26 +39,79 @@ func CompareHashWithClearPassword(hash string, clearPassword string) error {
 	return nil
 }
 
+//GenerateHashAndSaltKeyFromInput hashes the input with a new salt, using the default cost and no pepper
 func GenerateHashAndSaltKeyFromInput(input string) (string, string, error) {
+	return GenerateHashAndSaltKeyFromInputWithOptions(input, nil)
+}
+
+//GenerateHashAndSaltKeyFromInputWithOptions hashes the input with a new salt.
+//The input and salt are pre-hashed with HMAC-SHA256 keyed with the pepper before bcrypt,
+//so that inputs longer than 72 bytes do not get truncated and the salt always has an effect.
+func GenerateHashAndSaltKeyFromInputWithOptions(input string, options *HashOptions) (string, string, error) {
+	options = defaultHashOptions(options)
 
 	newSalt, err := random_utils.NewUUID()
 	if err != nil {
 		return "", "", err
 	}
-	hashedInput, err := GenerateHashFromString(fmt.Sprintf("%s:%s", input, newSalt))
+
+	hashedInput, err := bcrypt.GenerateFromPassword(preHash(fmt.Sprintf("%s:%s", input, newSalt), options.Pepper), options.Cost)
 	if err != nil {
 		return "", "", err
 	}
 
-	return hashedInput, newSalt, nil
+	return string(hashedInput), newSalt, nil
 }
 
+//CompareHashWithClearPasswordAndSalt compares a hash produced by GenerateHashAndSaltKeyFromInput,
+//including hashes produced before the pre-hash step was introduced
 func CompareHashWithClearPasswordAndSalt(hash string, clearPassword string, salt string) error {
+	_, err := CompareHashWithClearPasswordAndSaltWithOptions(hash, clearPassword, salt, nil)
+	return err
+}
 
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(fmt.Sprintf("%s:%s", clearPassword, salt)))
-	if err != nil {
-		return err
+//CompareHashWithClearPasswordAndSaltWithOptions compares a hash produced by GenerateHashAndSaltKeyFromInputWithOptions.
+//Hashes produced before the pre-hash step still verify, in which case needsMigration is true and the caller
+//should store a new hash generated from the clear password.
+func CompareHashWithClearPasswordAndSaltWithOptions(hash string, clearPassword string, salt string, options *HashOptions) (bool, error) {
+	options = defaultHashOptions(options)
+	input := fmt.Sprintf("%s:%s", clearPassword, salt)
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), preHash(input, options.Pepper))
+	if err == nil {
+		cost, costErr := bcrypt.Cost([]byte(hash))
+		return costErr == nil && cost != options.Cost, nil
+	}
+	if err != bcrypt.ErrMismatchedHashAndPassword {
+		return false, err
 	}
 
-	return nil
+	// compatibility path for hashes of the raw input, which bcrypt truncated at 72 bytes
+	legacyInput := []byte(input)
+	if len(legacyInput) > bcryptMaxInputLength {
+		legacyInput = legacyInput[:bcryptMaxInputLength]
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), legacyInput); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+//preHash returns the base64 encoded HMAC-SHA256 of the input keyed with the pepper, which always fits in bcrypt's 72 bytes
+func preHash(input string, pepper []byte) []byte {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(input))
+	return []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+//defaultHashOptions fills in the default cost
+func defaultHashOptions(options *HashOptions) *HashOptions {
+	if options == nil {
+		options = &HashOptions{}
+	}
+	if options.Cost == 0 {
+		return &HashOptions{Cost: bcrypt.DefaultCost, Pepper: options.Pepper}
+	}
+	return options
 }
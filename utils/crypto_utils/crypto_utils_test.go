@@ -2,6 +2,8 @@ package crypto_utils
 
 import (
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"testing"
 )
 
@@ -71,4 +73,64 @@ func TestCompareHashedPasswordSuccessful(t *testing.T){
 
 	err = CompareHashWithClearPasswordAndSalt(hashedInput, input, saltKey)
 	assert.Nil(t, err)
-}
\ No newline at end of file
+}
+func TestCompareHashedPasswordLongInputNoLongerTruncatedSuccessful(t *testing.T) {
+	//arrange
+	var prefix = strings.Repeat("a", 80)
+
+	//act
+	hashedInput, saltKey, err := GenerateHashAndSaltKeyFromInput(prefix + "-1")
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, CompareHashWithClearPasswordAndSalt(hashedInput, prefix+"-1", saltKey))
+	assert.NotNil(t, CompareHashWithClearPasswordAndSalt(hashedInput, prefix+"-2", saltKey))
+}
+
+func TestCompareHashedPasswordWithPepperSuccessful(t *testing.T) {
+	//arrange
+	var input = "my-input"
+	var options = &HashOptions{Cost: bcrypt.MinCost, Pepper: []byte("server-side-pepper")}
+
+	//act
+	hashedInput, saltKey, err := GenerateHashAndSaltKeyFromInputWithOptions(input, options)
+
+	//assert
+	assert.Nil(t, err)
+	needsMigration, err := CompareHashWithClearPasswordAndSaltWithOptions(hashedInput, input, saltKey, options)
+	assert.Nil(t, err)
+	assert.False(t, needsMigration)
+
+	_, err = CompareHashWithClearPasswordAndSaltWithOptions(hashedInput, input, saltKey, &HashOptions{Cost: bcrypt.MinCost})
+	assert.NotNil(t, err)
+}
+
+func TestCompareHashedPasswordLegacyHashNeedsMigrationSuccessful(t *testing.T) {
+	//arrange
+	var input = "my-input"
+	var saltKey = "0b5a2c5e-6a4b-4f3e-9c1d-2f8e7a6b5c4d"
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte(input+":"+saltKey), bcrypt.MinCost)
+	assert.Nil(t, err)
+
+	//act
+	needsMigration, err := CompareHashWithClearPasswordAndSaltWithOptions(string(legacyHash), input, saltKey, &HashOptions{Cost: bcrypt.MinCost})
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, needsMigration)
+	assert.Nil(t, CompareHashWithClearPasswordAndSalt(string(legacyHash), input, saltKey))
+}
+
+func TestCompareHashedPasswordCostChangeNeedsMigrationSuccessful(t *testing.T) {
+	//arrange
+	var input = "my-input"
+	hashedInput, saltKey, err := GenerateHashAndSaltKeyFromInputWithOptions(input, &HashOptions{Cost: bcrypt.MinCost})
+	assert.Nil(t, err)
+
+	//act
+	needsMigration, err := CompareHashWithClearPasswordAndSaltWithOptions(hashedInput, input, saltKey, &HashOptions{Cost: bcrypt.MinCost + 1})
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, needsMigration)
+}
@@ -0,0 +1,304 @@
+package crypto_utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/lelinu/api_utils/utils/base64_utils"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+//CipherAlgorithm authenticated encryption algorithm, stored in every ciphertext
+type CipherAlgorithm byte
+
+const (
+	AES256GCM         CipherAlgorithm = 1
+	XChaCha20Poly1305 CipherAlgorithm = 2
+
+	// first byte of every ciphertext, identifies its layout
+	keyringCiphertextVersion  byte = 1
+	envelopeCiphertextVersion byte = 2
+
+	EncryptionKeyLength = 32
+)
+
+var (
+	ErrInvalidCiphertext   = errors.New("crypto: invalid ciphertext")
+	ErrUnknownKeyID        = errors.New("crypto: unknown key id")
+	ErrUnsupportedCipher   = errors.New("crypto: unsupported cipher algorithm")
+	ErrInvalidKeyLength    = errors.New("crypto: encryption keys must be 32 bytes")
+	ErrNoPrimaryKey        = errors.New("crypto: keyring has no primary key")
+	ErrCannotRemovePrimary = errors.New("crypto: the primary key cannot be removed")
+	ErrDuplicateKeyID      = errors.New("crypto: key id already exists")
+)
+
+//newAEAD returns the aead for the algorithm
+func newAEAD(algorithm CipherAlgorithm, key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeyLength {
+		return nil, ErrInvalidKeyLength
+	}
+
+	switch algorithm {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, ErrUnsupportedCipher
+}
+
+//seal encrypts the plaintext with a random nonce, appending nonce and sealed data to dst
+func seal(dst []byte, aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, additionalData), nil
+}
+
+//open decrypts nonce and sealed data produced by seal
+func open(aead cipher.AEAD, data []byte, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
+
+//NewEncryptionKey returns a new random 32 byte key
+func NewEncryptionKey() ([]byte, error) {
+	key := make([]byte, EncryptionKeyLength)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+//keyringKey key held by the keyring
+type keyringKey struct {
+	algorithm CipherAlgorithm
+	aead      cipher.AEAD
+}
+
+//Keyring holds several keys identified by an ID, encrypting with the primary key and decrypting with any of them,
+//so that keys can be rotated without losing access to existing ciphertexts.
+//Ciphertexts are laid out as version(1) | algorithm(1) | key id length(1) | key id | nonce | sealed data.
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string]*keyringKey
+	primary string
+}
+
+//NewKeyring constructor for an empty keyring
+func NewKeyring() *Keyring {
+	return &Keyring{
+		keys: map[string]*keyringKey{},
+	}
+}
+
+//AddKey adds a 32 byte key, the first key added becomes the primary key. Ids cannot be reused, as ciphertexts
+//carrying the id would no longer decrypt, ErrDuplicateKeyID is returned instead.
+func (k *Keyring) AddKey(id string, key []byte, algorithm CipherAlgorithm) error {
+	if len(id) == 0 || len(id) > 255 {
+		return errors.New("crypto: key id must be between 1 and 255 bytes")
+	}
+
+	aead, err := newAEAD(algorithm, key)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; ok {
+		return ErrDuplicateKeyID
+	}
+	k.keys[id] = &keyringKey{algorithm: algorithm, aead: aead}
+	if k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+//SetPrimary sets the key used for new encryptions
+func (k *Keyring) SetPrimary(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; !ok {
+		return ErrUnknownKeyID
+	}
+	k.primary = id
+	return nil
+}
+
+//Rotate adds a key and makes it the primary key, previous keys remain available for decryption.
+//The id must be new, see AddKey.
+func (k *Keyring) Rotate(id string, key []byte, algorithm CipherAlgorithm) error {
+	if err := k.AddKey(id, key, algorithm); err != nil {
+		return err
+	}
+	return k.SetPrimary(id)
+}
+
+//RemoveKey removes a retired key, once every ciphertext using it has been re-encrypted
+func (k *Keyring) RemoveKey(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if id == k.primary {
+		return ErrCannotRemovePrimary
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+//PrimaryKeyID returns the id of the key used for new encryptions
+func (k *Keyring) PrimaryKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.primary
+}
+
+//Encrypt encrypts the plaintext with the primary key. additionalData is authenticated but not encrypted,
+//and must be passed again to Decrypt, such as the id of the row a field belongs to.
+func (k *Keyring) Encrypt(plaintext []byte, additionalData []byte) ([]byte, error) {
+	k.mu.RLock()
+	id := k.primary
+	key, ok := k.keys[id]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNoPrimaryKey
+	}
+
+	header := make([]byte, 0, 3+len(id))
+	header = append(header, keyringCiphertextVersion, byte(key.algorithm), byte(len(id)))
+	header = append(header, id...)
+
+	// the header is authenticated together with the additional data
+	return seal(header, key.aead, plaintext, append(append([]byte{}, header...), additionalData...))
+}
+
+//Decrypt decrypts a ciphertext produced by Encrypt with any key of the keyring
+func (k *Keyring) Decrypt(ciphertext []byte, additionalData []byte) ([]byte, error) {
+	id, algorithm, headerLength, err := parseKeyringHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[id]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if key.algorithm != algorithm {
+		return nil, ErrInvalidCiphertext
+	}
+
+	header := ciphertext[:headerLength]
+	return open(key.aead, ciphertext[headerLength:], append(append([]byte{}, header...), additionalData...))
+}
+
+//NeedsReEncryption reports whether the ciphertext was encrypted with a key other than the primary key
+func (k *Keyring) NeedsReEncryption(ciphertext []byte) bool {
+	id, err := CiphertextKeyID(ciphertext)
+	return err != nil || id != k.PrimaryKeyID()
+}
+
+//ReEncrypt decrypts the ciphertext and encrypts it again with the primary key, when it was encrypted with another key
+func (k *Keyring) ReEncrypt(ciphertext []byte, additionalData []byte) ([]byte, error) {
+	if !k.NeedsReEncryption(ciphertext) {
+		return ciphertext, nil
+	}
+
+	plaintext, err := k.Decrypt(ciphertext, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	return k.Encrypt(plaintext, additionalData)
+}
+
+//EncryptString encrypts a string field, returning base64 to store in a text column
+func (k *Keyring) EncryptString(plaintext string, additionalData []byte) (string, error) {
+	ciphertext, err := k.Encrypt([]byte(plaintext), additionalData)
+	if err != nil {
+		return "", err
+	}
+	return base64_utils.EncodeFromBytes(ciphertext), nil
+}
+
+//DecryptString decrypts a string field produced by EncryptString
+func (k *Keyring) DecryptString(ciphertext string, additionalData []byte) (string, error) {
+	data, err := base64_utils.DecodeToBytes(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := k.Decrypt(data, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+//CiphertextKeyID returns the id of the key a keyring ciphertext was encrypted with
+func CiphertextKeyID(ciphertext []byte) (string, error) {
+	id, _, _, err := parseKeyringHeader(ciphertext)
+	return id, err
+}
+
+//parseKeyringHeader returns the key id, algorithm and header length of a keyring ciphertext
+func parseKeyringHeader(ciphertext []byte) (string, CipherAlgorithm, int, error) {
+	if len(ciphertext) < 3 || ciphertext[0] != keyringCiphertextVersion {
+		return "", 0, 0, ErrInvalidCiphertext
+	}
+
+	headerLength := 3 + int(ciphertext[2])
+	if len(ciphertext) < headerLength {
+		return "", 0, 0, ErrInvalidCiphertext
+	}
+
+	return string(ciphertext[3:headerLength]), CipherAlgorithm(ciphertext[1]), headerLength, nil
+}
+
+//envelopeHeader returns version(1) | algorithm(1) | wrapped key length(2) | wrapped key
+func envelopeHeader(algorithm CipherAlgorithm, wrappedKey []byte) ([]byte, error) {
+	if len(wrappedKey) > 0xffff {
+		return nil, fmt.Errorf("crypto: wrapped key of %d bytes is too long", len(wrappedKey))
+	}
+
+	header := make([]byte, 4, 4+len(wrappedKey))
+	header[0] = envelopeCiphertextVersion
+	header[1] = byte(algorithm)
+	binary.BigEndian.PutUint16(header[2:], uint16(len(wrappedKey)))
+	return append(header, wrappedKey...), nil
+}
+
+//parseEnvelopeHeader returns the algorithm, wrapped key and header length of an envelope ciphertext
+func parseEnvelopeHeader(ciphertext []byte) (CipherAlgorithm, []byte, int, error) {
+	if len(ciphertext) < 4 || ciphertext[0] != envelopeCiphertextVersion {
+		return 0, nil, 0, ErrInvalidCiphertext
+	}
+
+	headerLength := 4 + int(binary.BigEndian.Uint16(ciphertext[2:4]))
+	if len(ciphertext) < headerLength {
+		return 0, nil, 0, ErrInvalidCiphertext
+	}
+
+	return CipherAlgorithm(ciphertext[1]), ciphertext[4:headerLength], headerLength, nil
+}
@@ -0,0 +1,139 @@
+package crypto_utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeyring(t *testing.T, id string, algorithm CipherAlgorithm) *Keyring {
+	key, err := NewEncryptionKey()
+	assert.Nil(t, err)
+
+	keyring := NewKeyring()
+	assert.Nil(t, keyring.AddKey(id, key, algorithm))
+	return keyring
+}
+
+func TestKeyringEncryptDecryptSuccessful(t *testing.T) {
+	for _, algorithm := range []CipherAlgorithm{AES256GCM, XChaCha20Poly1305} {
+		//arrange
+		keyring := newTestKeyring(t, "key-1", algorithm)
+
+		//act
+		ciphertext, err := keyring.Encrypt([]byte("4111 1111 1111 1111"), []byte("user:1"))
+		assert.Nil(t, err)
+		plaintext, err := keyring.Decrypt(ciphertext, []byte("user:1"))
+
+		//assert
+		assert.Nil(t, err)
+		assert.EqualValues(t, "4111 1111 1111 1111", string(plaintext))
+		keyID, err := CiphertextKeyID(ciphertext)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "key-1", keyID)
+		assert.EqualValues(t, keyringCiphertextVersion, ciphertext[0])
+		assert.EqualValues(t, algorithm, ciphertext[1])
+	}
+}
+
+func TestKeyringDecryptWrongAdditionalDataSuccessful(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "key-1", AES256GCM)
+	ciphertext, _ := keyring.Encrypt([]byte("secret"), []byte("user:1"))
+
+	//act
+	plaintext, err := keyring.Decrypt(ciphertext, []byte("user:2"))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Nil(t, plaintext)
+}
+
+func TestKeyringDecryptTamperedCiphertextSuccessful(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "key-1", AES256GCM)
+	ciphertext, _ := keyring.Encrypt([]byte("secret"), nil)
+	ciphertext[len(ciphertext)-1] ^= 0xff
+
+	//act
+	_, err := keyring.Decrypt(ciphertext, nil)
+	_, truncatedErr := keyring.Decrypt(ciphertext[:5], nil)
+	_, versionErr := keyring.Decrypt([]byte{9, 1, 0}, nil)
+
+	//assert
+	assert.NotNil(t, err)
+	assert.EqualValues(t, ErrInvalidCiphertext, truncatedErr)
+	assert.EqualValues(t, ErrInvalidCiphertext, versionErr)
+}
+
+func TestKeyringRotationAndReEncryptSuccessful(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "key-1", AES256GCM)
+	oldCiphertext, _ := keyring.Encrypt([]byte("secret"), nil)
+	newKey, _ := NewEncryptionKey()
+
+	//act
+	assert.Nil(t, keyring.Rotate("key-2", newKey, XChaCha20Poly1305))
+	plaintext, err := keyring.Decrypt(oldCiphertext, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "secret", string(plaintext))
+	assert.True(t, keyring.NeedsReEncryption(oldCiphertext))
+
+	newCiphertext, err := keyring.ReEncrypt(oldCiphertext, nil)
+
+	//assert
+	assert.Nil(t, err)
+	assert.False(t, keyring.NeedsReEncryption(newCiphertext))
+	assert.EqualValues(t, ErrCannotRemovePrimary, keyring.RemoveKey("key-2"))
+	assert.Nil(t, keyring.RemoveKey("key-1"))
+	_, err = keyring.Decrypt(oldCiphertext, nil)
+	assert.EqualValues(t, ErrUnknownKeyID, err)
+	plaintext, err = keyring.Decrypt(newCiphertext, nil)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "secret", string(plaintext))
+}
+
+func TestKeyringEncryptStringSuccessful(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "key-1", AES256GCM)
+
+	//act
+	ciphertext, err := keyring.EncryptString("john@doe.com", nil)
+	assert.Nil(t, err)
+	plaintext, err := keyring.DecryptString(ciphertext, nil)
+
+	//assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, "john@doe.com", plaintext)
+}
+
+func TestKeyringInvalidKeySuccessful(t *testing.T) {
+	//arrange
+	keyring := NewKeyring()
+
+	//act
+	err := keyring.AddKey("key-1", []byte("short"), AES256GCM)
+	_, encryptErr := keyring.Encrypt([]byte("secret"), nil)
+
+	//assert
+	assert.EqualValues(t, ErrInvalidKeyLength, err)
+	assert.EqualValues(t, ErrNoPrimaryKey, encryptErr)
+}
+
+func TestKeyringDuplicateKeyID(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "key-1", AES256GCM)
+	ciphertext, _ := keyring.Encrypt([]byte("secret"), nil)
+	otherKey, _ := NewEncryptionKey()
+
+	//act
+	addErr := keyring.AddKey("key-1", otherKey, AES256GCM)
+	rotateErr := keyring.Rotate("key-1", otherKey, XChaCha20Poly1305)
+	plaintext, err := keyring.Decrypt(ciphertext, nil)
+
+	//assert
+	assert.EqualValues(t, ErrDuplicateKeyID, addErr)
+	assert.EqualValues(t, ErrDuplicateKeyID, rotateErr)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "secret", string(plaintext))
+}
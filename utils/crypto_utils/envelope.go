@@ -0,0 +1,131 @@
+package crypto_utils
+
+import (
+	"context"
+)
+
+//KeyProvider wraps and unwraps data keys with a master key that never leaves the provider,
+//the wrapped key must identify the master key it was wrapped with
+type KeyProvider interface {
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+//LocalKeyProvider key provider wrapping data keys with the master keys of a keyring held in memory
+type LocalKeyProvider struct {
+	keyring *Keyring
+}
+
+//NewLocalKeyProvider constructor, rotating the keyring rotates the master key for new data keys
+func NewLocalKeyProvider(keyring *Keyring) *LocalKeyProvider {
+	return &LocalKeyProvider{keyring: keyring}
+}
+
+//WrapKey encrypts the data key with the keyring primary key
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.keyring.Encrypt(dataKey, nil)
+}
+
+//UnwrapKey decrypts the data key with the keyring key it was wrapped with
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.keyring.Decrypt(wrappedKey, nil)
+}
+
+//EnvelopeEncrypter encrypts every payload with a new random data key, stored wrapped by the master key provider
+//next to the data. Ciphertexts are laid out as
+//version(1) | algorithm(1) | wrapped key length(2) | wrapped key | nonce | sealed data.
+type EnvelopeEncrypter struct {
+	provider  KeyProvider
+	algorithm CipherAlgorithm
+}
+
+//NewEnvelopeEncrypter constructor
+func NewEnvelopeEncrypter(provider KeyProvider, algorithm CipherAlgorithm) (*EnvelopeEncrypter, error) {
+	if algorithm != AES256GCM && algorithm != XChaCha20Poly1305 {
+		return nil, ErrUnsupportedCipher
+	}
+
+	return &EnvelopeEncrypter{
+		provider:  provider,
+		algorithm: algorithm,
+	}, nil
+}
+
+//Encrypt encrypts the plaintext, such as a file, with a new data key
+func (e *EnvelopeEncrypter) Encrypt(ctx context.Context, plaintext []byte, additionalData []byte) ([]byte, error) {
+	dataKey, err := NewEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(e.algorithm, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := envelopeHeader(e.algorithm, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// the header is not authenticated so that ReWrap can replace it, a tampered wrapped key
+	// or algorithm yields another key or cipher and fails to open the data
+	return seal(header, aead, plaintext, additionalData)
+}
+
+//Decrypt unwraps the data key through the provider and decrypts the ciphertext
+func (e *EnvelopeEncrypter) Decrypt(ctx context.Context, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	algorithm, wrappedKey, headerLength, err := parseEnvelopeHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(algorithm, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, ciphertext[headerLength:], additionalData)
+}
+
+//ReWrap unwraps the data key and wraps it again with the provider's current master key.
+//The data itself is not re-encrypted, which makes master key rotation cheap for large files.
+func (e *EnvelopeEncrypter) ReWrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	algorithm, wrappedKey, headerLength, err := parseEnvelopeHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	newWrappedKey, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := envelopeHeader(algorithm, newWrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, ciphertext[headerLength:]...), nil
+}
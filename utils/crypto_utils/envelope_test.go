@@ -0,0 +1,67 @@
+package crypto_utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeEncryptDecryptSuccessful(t *testing.T) {
+	for _, algorithm := range []CipherAlgorithm{AES256GCM, XChaCha20Poly1305} {
+		//arrange
+		provider := NewLocalKeyProvider(newTestKeyring(t, "master-1", AES256GCM))
+		encrypter, err := NewEnvelopeEncrypter(provider, algorithm)
+		assert.Nil(t, err)
+		file := bytes.Repeat([]byte("file content "), 1000)
+
+		//act
+		ciphertext, err := encrypter.Encrypt(context.Background(), file, []byte("uploads/a.pdf"))
+		assert.Nil(t, err)
+		plaintext, err := encrypter.Decrypt(context.Background(), ciphertext, []byte("uploads/a.pdf"))
+
+		//assert
+		assert.Nil(t, err)
+		assert.EqualValues(t, file, plaintext)
+		assert.EqualValues(t, envelopeCiphertextVersion, ciphertext[0])
+	}
+}
+
+func TestEnvelopeReWrapAfterMasterKeyRotationSuccessful(t *testing.T) {
+	//arrange
+	keyring := newTestKeyring(t, "master-1", AES256GCM)
+	encrypter, _ := NewEnvelopeEncrypter(NewLocalKeyProvider(keyring), AES256GCM)
+	ciphertext, err := encrypter.Encrypt(context.Background(), []byte("secret"), nil)
+	assert.Nil(t, err)
+	newMasterKey, _ := NewEncryptionKey()
+	assert.Nil(t, keyring.Rotate("master-2", newMasterKey, AES256GCM))
+
+	//act
+	rewrapped, err := encrypter.ReWrap(context.Background(), ciphertext)
+	assert.Nil(t, err)
+	assert.Nil(t, keyring.RemoveKey("master-1"))
+	plaintext, err := encrypter.Decrypt(context.Background(), rewrapped, nil)
+
+	//assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, "secret", string(plaintext))
+	_, err = encrypter.Decrypt(context.Background(), ciphertext, nil)
+	assert.EqualValues(t, ErrUnknownKeyID, err)
+}
+
+func TestEnvelopeDecryptInvalidSuccessful(t *testing.T) {
+	//arrange
+	encrypter, _ := NewEnvelopeEncrypter(NewLocalKeyProvider(newTestKeyring(t, "master-1", AES256GCM)), AES256GCM)
+	ciphertext, _ := encrypter.Encrypt(context.Background(), []byte("secret"), []byte("a"))
+
+	//act
+	_, adErr := encrypter.Decrypt(context.Background(), ciphertext, []byte("b"))
+	_, formatErr := encrypter.Decrypt(context.Background(), []byte{2, 1, 0xff, 0xff}, nil)
+	_, algorithmErr := NewEnvelopeEncrypter(nil, CipherAlgorithm(9))
+
+	//assert
+	assert.NotNil(t, adErr)
+	assert.EqualValues(t, ErrInvalidCiphertext, formatErr)
+	assert.EqualValues(t, ErrUnsupportedCipher, algorithmErr)
+}
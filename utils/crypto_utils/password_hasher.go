@@ -0,0 +1,425 @@
+package crypto_utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	AlgorithmBcrypt       = "bcrypt"
+	AlgorithmBcryptSHA256 = "bcrypt-sha256"
+	AlgorithmArgon2id     = "argon2id"
+	AlgorithmScrypt       = "scrypt"
+
+	DefaultArgon2idTime    = 3
+	DefaultArgon2idMemory  = 64 * 1024
+	DefaultArgon2idThreads = 2
+	DefaultScryptN         = 32768
+	DefaultScryptR         = 8
+	DefaultScryptP         = 1
+	DefaultSaltLength      = 16
+	DefaultKeyLength       = 32
+)
+
+var (
+	ErrInvalidHash          = errors.New("crypto: invalid hash format")
+	ErrUnsupportedAlgorithm = errors.New("crypto: unsupported hash algorithm")
+	ErrIncompatibleVersion  = errors.New("crypto: incompatible argon2 version")
+
+	// phc salts and hashes are encoded in unpadded standard base64
+	phcEncoding = base64.RawStdEncoding
+)
+
+//Hasher hashes passwords into self describing strings holding the algorithm and its parameters
+type Hasher interface {
+	// Algorithm returns the algorithm identifier stored in the hash
+	Algorithm() string
+	// Hash hashes the password with the hasher parameters
+	Hash(password string) (string, error)
+	// Verify compares the password with a hash produced by this algorithm, using the parameters stored in the hash
+	Verify(hash string, password string) (bool, error)
+	// NeedsRehash reports whether the hash was produced with different parameters than the hasher ones
+	NeedsRehash(hash string) bool
+}
+
+//BcryptHasher hashes passwords with bcrypt, stored in the native $2a$ format
+type BcryptHasher struct {
+	Cost int
+}
+
+//NewBcryptHasher constructor with bcrypt.DefaultCost
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{Cost: bcrypt.DefaultCost}
+}
+
+//Algorithm returns bcrypt
+func (h *BcryptHasher) Algorithm() string {
+	return AlgorithmBcrypt
+}
+
+//Hash hashes the password with bcrypt
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+//Verify compares the password with a bcrypt hash
+func (h *BcryptHasher) Verify(hash string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//NeedsRehash reports whether the bcrypt cost differs from the hasher cost
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+//BcryptSHA256Hasher hashes passwords with bcrypt after an HMAC-SHA256 pre-hash keyed with an optional pepper,
+//so that passwords longer than 72 bytes are not truncated. Stored as $bcrypt-sha256$v=1,t=2a,r=10$salt$hash
+type BcryptSHA256Hasher struct {
+	Cost   int
+	Pepper []byte
+}
+
+//NewBcryptSHA256Hasher constructor with bcrypt.DefaultCost and the given pepper, which may be nil
+func NewBcryptSHA256Hasher(pepper []byte) *BcryptSHA256Hasher {
+	return &BcryptSHA256Hasher{Cost: bcrypt.DefaultCost, Pepper: pepper}
+}
+
+//Algorithm returns bcrypt-sha256
+func (h *BcryptSHA256Hasher) Algorithm() string {
+	return AlgorithmBcryptSHA256
+}
+
+//Hash hashes the pre-hashed password with bcrypt
+func (h *BcryptSHA256Hasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword(preHash(password, h.Pepper), h.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	// native format is $2a$10$ followed by 22 characters of salt and 31 characters of hash
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 4 || len(parts[3]) != 53 {
+		return "", ErrInvalidHash
+	}
+
+	return fmt.Sprintf("$%s$v=1,t=%s,r=%s$%s$%s", AlgorithmBcryptSHA256, parts[1], parts[2], parts[3][:22], parts[3][22:]), nil
+}
+
+//Verify compares the pre-hashed password with a bcrypt-sha256 hash
+func (h *BcryptSHA256Hasher) Verify(hash string, password string) (bool, error) {
+	native, _, err := parseBcryptSHA256Hash(hash)
+	if err != nil {
+		return false, err
+	}
+	return NewBcryptHasher().Verify(native, string(preHash(password, h.Pepper)))
+}
+
+//NeedsRehash reports whether the bcrypt cost differs from the hasher cost
+func (h *BcryptSHA256Hasher) NeedsRehash(hash string) bool {
+	_, cost, err := parseBcryptSHA256Hash(hash)
+	return err != nil || cost != h.Cost
+}
+
+//parseBcryptSHA256Hash parses $bcrypt-sha256$v=1,t=2a,r=10$salt$hash and returns the native bcrypt hash and its cost
+func parseBcryptSHA256Hash(hash string) (string, int, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[1] != AlgorithmBcryptSHA256 || len(parts[3]) != 22 || len(parts[4]) != 31 {
+		return "", 0, ErrInvalidHash
+	}
+
+	var version, cost int
+	var variant string
+	params := strings.Split(parts[2], ",")
+	if len(params) != 3 {
+		return "", 0, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(params[0], "v=%d", &version); err != nil || version != 1 {
+		return "", 0, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(params[1], "t=%s", &variant); err != nil {
+		return "", 0, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(params[2], "r=%d", &cost); err != nil {
+		return "", 0, ErrInvalidHash
+	}
+
+	return fmt.Sprintf("$%s$%02d$%s%s", variant, cost, parts[3], parts[4]), cost, nil
+}
+
+//Argon2idHasher hashes passwords with argon2id, stored as $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+type Argon2idHasher struct {
+	Time       uint32
+	Memory     uint32
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+//NewArgon2idHasher constructor with the default parameters
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Time:       DefaultArgon2idTime,
+		Memory:     DefaultArgon2idMemory,
+		Threads:    DefaultArgon2idThreads,
+		SaltLength: DefaultSaltLength,
+		KeyLength:  DefaultKeyLength,
+	}
+}
+
+//Algorithm returns argon2id
+func (h *Argon2idHasher) Algorithm() string {
+	return AlgorithmArgon2id
+}
+
+//Hash hashes the password with argon2id
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt, err := newSalt(h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version,
+		h.Memory, h.Time, h.Threads, phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key)), nil
+}
+
+//Verify compares the password with an argon2id hash
+func (h *Argon2idHasher) Verify(hash string, password string) (bool, error) {
+	params, salt, key, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+//NeedsRehash reports whether the argon2id parameters differ from the hasher parameters
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := parseArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.Time != h.Time || params.Memory != h.Memory || params.Threads != h.Threads ||
+		uint32(len(salt)) != h.SaltLength || uint32(len(key)) != h.KeyLength
+}
+
+//parseArgon2idHash parses $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+func parseArgon2idHash(hash string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, ErrIncompatibleVersion
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if params.Time == 0 || params.Threads == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[4], parts[5])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return params, salt, key, nil
+}
+
+//ScryptHasher hashes passwords with scrypt, stored as $scrypt$ln=15,r=8,p=1$salt$hash where ln is log2(N)
+type ScryptHasher struct {
+	N          int
+	R          int
+	P          int
+	SaltLength uint32
+	KeyLength  int
+}
+
+//NewScryptHasher constructor with the default parameters
+func NewScryptHasher() *ScryptHasher {
+	return &ScryptHasher{
+		N:          DefaultScryptN,
+		R:          DefaultScryptR,
+		P:          DefaultScryptP,
+		SaltLength: DefaultSaltLength,
+		KeyLength:  DefaultKeyLength,
+	}
+}
+
+//Algorithm returns scrypt
+func (h *ScryptHasher) Algorithm() string {
+	return AlgorithmScrypt
+}
+
+//Hash hashes the password with scrypt
+func (h *ScryptHasher) Hash(password string) (string, error) {
+	if h.N <= 1 || h.N&(h.N-1) != 0 {
+		return "", errors.New("crypto: scrypt N must be a power of two greater than 1")
+	}
+
+	salt, err := newSalt(h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, h.N, h.R, h.P, h.KeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s", AlgorithmScrypt, bits.TrailingZeros(uint(h.N)), h.R, h.P,
+		phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key)), nil
+}
+
+//Verify compares the password with a scrypt hash
+func (h *ScryptHasher) Verify(hash string, password string) (bool, error) {
+	params, salt, key, err := parseScryptHash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, len(key))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+//NeedsRehash reports whether the scrypt parameters differ from the hasher parameters
+func (h *ScryptHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := parseScryptHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.N != h.N || params.R != h.R || params.P != h.P ||
+		uint32(len(salt)) != h.SaltLength || len(key) != h.KeyLength
+}
+
+//parseScryptHash parses $scrypt$ln=15,r=8,p=1$salt$hash
+func parseScryptHash(hash string) (*ScryptHasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[1] != AlgorithmScrypt {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var ln uint
+	params := &ScryptHasher{}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &ln, &params.R, &params.P); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if ln == 0 || ln > 31 || params.R <= 0 || params.P <= 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	params.N = 1 << ln
+
+	salt, key, err := decodeSaltAndKey(parts[3], parts[4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return params, salt, key, nil
+}
+
+//IdentifyAlgorithm returns the algorithm a hash was produced with
+func IdentifyAlgorithm(hash string) (string, error) {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return AlgorithmBcrypt, nil
+	case strings.HasPrefix(hash, "$"+AlgorithmBcryptSHA256+"$"):
+		return AlgorithmBcryptSHA256, nil
+	case strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$"):
+		return AlgorithmArgon2id, nil
+	case strings.HasPrefix(hash, "$"+AlgorithmScrypt+"$"):
+		return AlgorithmScrypt, nil
+	}
+	return "", ErrUnsupportedAlgorithm
+}
+
+//Verify compares the password with a hash produced by any of the supported hashers, dispatching on the stored algorithm
+func Verify(hash string, password string) (bool, error) {
+	return VerifyWithPepper(hash, password, nil)
+}
+
+//VerifyWithPepper same as Verify, using the pepper for the algorithms that support one
+func VerifyWithPepper(hash string, password string, pepper []byte) (bool, error) {
+	algorithm, err := IdentifyAlgorithm(hash)
+	if err != nil {
+		return false, err
+	}
+
+	switch algorithm {
+	case AlgorithmBcryptSHA256:
+		return NewBcryptSHA256Hasher(pepper).Verify(hash, password)
+	case AlgorithmArgon2id:
+		return NewArgon2idHasher().Verify(hash, password)
+	case AlgorithmScrypt:
+		return NewScryptHasher().Verify(hash, password)
+	default:
+		return NewBcryptHasher().Verify(hash, password)
+	}
+}
+
+//NeedsRehash reports whether a stored hash should be replaced by one produced with the current hasher,
+//either because it uses another algorithm or outdated parameters. Call it after a successful Verify at login.
+func NeedsRehash(hash string, current Hasher) bool {
+	algorithm, err := IdentifyAlgorithm(hash)
+	if err != nil || algorithm != current.Algorithm() {
+		return true
+	}
+	return current.NeedsRehash(hash)
+}
+
+//newSalt returns length random bytes
+func newSalt(length uint32) ([]byte, error) {
+	if length == 0 {
+		length = DefaultSaltLength
+	}
+	salt := make([]byte, length)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+//decodeSaltAndKey decodes the phc salt and hash segments
+func decodeSaltAndKey(encodedSalt string, encodedKey string) ([]byte, []byte, error) {
+	salt, err := phcEncoding.DecodeString(encodedSalt)
+	if err != nil || len(salt) == 0 {
+		return nil, nil, ErrInvalidHash
+	}
+	key, err := phcEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) == 0 {
+		return nil, nil, ErrInvalidHash
+	}
+	return salt, key, nil
+}
@@ -0,0 +1,135 @@
+package crypto_utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{Time: 1, Memory: 1024, Threads: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func newTestScryptHasher() *ScryptHasher {
+	return &ScryptHasher{N: 1024, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestHashersHashAndVerifySuccessful(t *testing.T) {
+	hashers := []Hasher{&BcryptHasher{Cost: bcrypt.MinCost}, &BcryptSHA256Hasher{Cost: bcrypt.MinCost, Pepper: []byte("pepper")},
+		newTestArgon2idHasher(), newTestScryptHasher()}
+
+	for _, hasher := range hashers {
+		// act
+		hash, err := hasher.Hash("my-input")
+
+		// assert
+		assert.Nil(t, err, hasher.Algorithm())
+		algorithm, err := IdentifyAlgorithm(hash)
+		assert.Nil(t, err)
+		assert.EqualValues(t, hasher.Algorithm(), algorithm)
+
+		valid, err := hasher.Verify(hash, "my-input")
+		assert.Nil(t, err)
+		assert.True(t, valid, hasher.Algorithm())
+
+		valid, err = hasher.Verify(hash, "other-input")
+		assert.Nil(t, err)
+		assert.False(t, valid, hasher.Algorithm())
+
+		assert.False(t, hasher.NeedsRehash(hash), hasher.Algorithm())
+	}
+}
+
+func TestArgon2idHashFormatSuccessful(t *testing.T) {
+	// act
+	hash, err := newTestArgon2idHasher().Hash("my-input")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.EqualValues(t, 6, len(strings.Split(hash, "$")))
+}
+
+func TestScryptHashFormatSuccessful(t *testing.T) {
+	// act
+	hash, err := newTestScryptHasher().Hash("my-input")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$scrypt$ln=10,r=8,p=1$"))
+}
+
+func TestVerifyDispatchesOnStoredAlgorithmSuccessful(t *testing.T) {
+	// arrange
+	argonHash, _ := newTestArgon2idHasher().Hash("my-input")
+	scryptHash, _ := newTestScryptHasher().Hash("my-input")
+	bcryptHash, _ := GenerateHashFromString("my-input")
+
+	for _, hash := range []string{argonHash, scryptHash, bcryptHash} {
+		// act
+		valid, err := Verify(hash, "my-input")
+
+		// assert
+		assert.Nil(t, err)
+		assert.True(t, valid, hash)
+	}
+}
+
+func TestVerifyInvalidHashSuccessful(t *testing.T) {
+	// act
+	_, err := Verify("$md5$abc", "my-input")
+	_, argonErr := Verify("$argon2id$v=19$m=1024$bad", "my-input")
+	_, versionErr := Verify("$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5", "my-input")
+
+	// assert
+	assert.EqualValues(t, ErrUnsupportedAlgorithm, err)
+	assert.EqualValues(t, ErrInvalidHash, argonErr)
+	assert.EqualValues(t, ErrIncompatibleVersion, versionErr)
+}
+
+func TestNeedsRehashSuccessful(t *testing.T) {
+	// arrange
+	current := newTestArgon2idHasher()
+	legacyHash, _ := (&BcryptHasher{Cost: bcrypt.MinCost}).Hash("my-input")
+	weakHash, _ := (&Argon2idHasher{Time: 1, Memory: 512, Threads: 1, SaltLength: 16, KeyLength: 32}).Hash("my-input")
+	currentHash, _ := current.Hash("my-input")
+
+	// act / assert
+	assert.True(t, NeedsRehash(legacyHash, current))
+	assert.True(t, NeedsRehash(weakHash, current))
+	assert.False(t, NeedsRehash(currentHash, current))
+	assert.True(t, NeedsRehash(legacyHash, NewBcryptHasher()))
+}
+
+func TestBcryptSHA256HasherLongPasswordSuccessful(t *testing.T) {
+	// arrange
+	hasher := &BcryptSHA256Hasher{Cost: bcrypt.MinCost}
+	prefix := strings.Repeat("a", 80)
+
+	// act
+	hash, err := hasher.Hash(prefix + "-1")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$bcrypt-sha256$v=1,t=2a,r=04$"))
+	valid, _ := hasher.Verify(hash, prefix+"-1")
+	assert.True(t, valid)
+	valid, _ = hasher.Verify(hash, prefix+"-2")
+	assert.False(t, valid)
+}
+
+func TestVerifyWithPepperSuccessful(t *testing.T) {
+	// arrange
+	hash, _ := (&BcryptSHA256Hasher{Cost: bcrypt.MinCost, Pepper: []byte("pepper")}).Hash("my-input")
+
+	// act
+	validWithPepper, err := VerifyWithPepper(hash, "my-input", []byte("pepper"))
+	validWithoutPepper, _ := Verify(hash, "my-input")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, validWithPepper)
+	assert.False(t, validWithoutPepper)
+}
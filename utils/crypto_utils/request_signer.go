@@ -0,0 +1,300 @@
+package crypto_utils
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/random_utils"
+)
+
+const (
+	SignatureHeader           = "X-Signature"
+	DefaultSignatureTolerance = 5 * time.Minute
+	signatureSchemeV1         = "v1"
+	signatureNonceLength      = 16
+)
+
+var (
+	ErrMissingSignature   = errors.New("crypto: missing signature")
+	ErrMalformedSignature = errors.New("crypto: malformed signature header")
+	ErrSignatureExpired   = errors.New("crypto: signature timestamp outside of the tolerance window")
+	ErrSignatureMismatch  = errors.New("crypto: signature does not match")
+	ErrSignatureReplayed  = errors.New("crypto: signature has already been used")
+	ErrNoSigningSecret    = errors.New("crypto: at least one signing secret is required")
+)
+
+//NonceCache remembers nonces until they expire, used to reject replayed requests
+type NonceCache interface {
+	// Add stores the nonce and returns false when it was already stored and has not expired
+	Add(nonce string, expiresAt time.Time) bool
+}
+
+//MemoryNonceCache in memory NonceCache for a single instance
+type MemoryNonceCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	expiry nonceExpiryQueue
+	clock  date_utils.Clock
+}
+
+//NewMemoryNonceCache constructor
+func NewMemoryNonceCache() *MemoryNonceCache {
+	return &MemoryNonceCache{
+		nonces: map[string]time.Time{},
+		clock:  date_utils.RealClock{},
+	}
+}
+
+//SetClock replaces the clock deciding which nonces have expired
+func (c *MemoryNonceCache) SetClock(clock date_utils.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+//Add stores the nonce, evicting expired ones. Nonces are queued by expiry so that only the expired ones are visited.
+func (c *MemoryNonceCache) Add(nonce string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for len(c.expiry) > 0 && !c.expiry[0].expiresAt.After(now) {
+		entry := heap.Pop(&c.expiry).(nonceExpiry)
+		// the nonce may have been stored again since this entry was queued
+		if expiry, ok := c.nonces[entry.nonce]; ok && expiry.Equal(entry.expiresAt) {
+			delete(c.nonces, entry.nonce)
+		}
+	}
+
+	if _, ok := c.nonces[nonce]; ok {
+		return false
+	}
+	c.nonces[nonce] = expiresAt
+	heap.Push(&c.expiry, nonceExpiry{nonce: nonce, expiresAt: expiresAt})
+	return true
+}
+
+//Len returns the number of nonces held, expired ones included until the next Add
+func (c *MemoryNonceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.nonces)
+}
+
+//nonceExpiry nonce queued for eviction
+type nonceExpiry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+//nonceExpiryQueue min heap of nonces ordered by expiry
+type nonceExpiryQueue []nonceExpiry
+
+func (q nonceExpiryQueue) Len() int           { return len(q) }
+func (q nonceExpiryQueue) Less(i, j int) bool { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q nonceExpiryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *nonceExpiryQueue) Push(x interface{}) {
+	*q = append(*q, x.(nonceExpiry))
+}
+
+func (q *nonceExpiryQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+//RequestSigner signs and verifies requests, such as webhooks, with HMAC-SHA256 over the method, path,
+//timestamp, nonce and body. The signature header looks like t=1600000000,n=nonce,v1=hex[,v1=hex].
+type RequestSigner struct {
+	secrets    [][]byte
+	tolerance  time.Duration
+	nonceCache NonceCache
+	clock      date_utils.Clock
+}
+
+//NewRequestSigner constructor, the first secret is the current one. While rotating, pass the new and the old
+//secrets: requests are signed with every secret and verify against any of them.
+func NewRequestSigner(secrets ...string) (*RequestSigner, error) {
+	signer := &RequestSigner{
+		tolerance: DefaultSignatureTolerance,
+		clock:     date_utils.RealClock{},
+	}
+
+	for _, secret := range secrets {
+		if strings.TrimSpace(secret) != "" {
+			signer.secrets = append(signer.secrets, []byte(secret))
+		}
+	}
+	if len(signer.secrets) == 0 {
+		return nil, ErrNoSigningSecret
+	}
+
+	return signer, nil
+}
+
+//SetTolerance sets how far the signature timestamp can be from the current time
+func (s *RequestSigner) SetTolerance(tolerance time.Duration) {
+	s.tolerance = tolerance
+}
+
+//SetNonceCache enables replay protection, every nonce is then accepted only once within the tolerance window
+func (s *RequestSigner) SetNonceCache(nonceCache NonceCache) {
+	s.nonceCache = nonceCache
+}
+
+//SetClock replaces the clock used for the signature timestamps
+func (s *RequestSigner) SetClock(clock date_utils.Clock) {
+	s.clock = clock
+}
+
+//Sign returns the signature header value for the request parts, failing when no nonce can be generated
+func (s *RequestSigner) Sign(method string, path string, body []byte) (string, error) {
+	timestamp := s.clock.Now().Unix()
+	nonce, err := random_utils.NewRandomStringWithAlphabet(random_utils.AlphanumericAlphabet, signatureNonceLength)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{"t=" + strconv.FormatInt(timestamp, 10), "n=" + nonce}
+	for _, secret := range s.secrets {
+		parts = append(parts, signatureSchemeV1+"="+hex.EncodeToString(computeSignature(secret, timestamp, nonce, method, path, body)))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+//SignRequest sets the signature header on an outgoing request, the body is read and restored
+func (s *RequestSigner) SignRequest(r *http.Request) error {
+	body, err := readAndRestoreBody(r)
+	if err != nil {
+		return err
+	}
+
+	header, err := s.Sign(r.Method, r.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(SignatureHeader, header)
+	return nil
+}
+
+//Verify checks the signature header value against the request parts
+func (s *RequestSigner) Verify(header string, method string, path string, body []byte) error {
+	if strings.TrimSpace(header) == "" {
+		return ErrMissingSignature
+	}
+
+	var timestamp int64
+	var nonce string
+	var signatures [][]byte
+	var err error
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return ErrMalformedSignature
+		}
+		switch kv[0] {
+		case "t":
+			if timestamp, err = strconv.ParseInt(kv[1], 10, 64); err != nil {
+				return ErrMalformedSignature
+			}
+		case "n":
+			nonce = kv[1]
+		case signatureSchemeV1:
+			signature, err := hex.DecodeString(kv[1])
+			if err != nil {
+				return ErrMalformedSignature
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrMalformedSignature
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	now := s.clock.Now()
+	if now.Sub(signedAt) > s.tolerance || signedAt.Sub(now) > s.tolerance {
+		return ErrSignatureExpired
+	}
+
+	if !s.matchesAnySecret(signatures, timestamp, nonce, method, path, body) {
+		return ErrSignatureMismatch
+	}
+
+	// only checked once the signature is valid, so that forged requests cannot fill the cache
+	if s.nonceCache != nil {
+		if nonce == "" {
+			return ErrMalformedSignature
+		}
+		if !s.nonceCache.Add(nonce, signedAt.Add(s.tolerance)) {
+			return ErrSignatureReplayed
+		}
+	}
+
+	return nil
+}
+
+//VerifyRequest checks the signature header of an incoming request, the body is read and restored
+func (s *RequestSigner) VerifyRequest(r *http.Request) error {
+	body, err := readAndRestoreBody(r)
+	if err != nil {
+		return err
+	}
+
+	return s.Verify(r.Header.Get(SignatureHeader), r.Method, r.URL.RequestURI(), body)
+}
+
+//matchesAnySecret compares every signature with every secret in constant time
+func (s *RequestSigner) matchesAnySecret(signatures [][]byte, timestamp int64, nonce string, method string, path string, body []byte) bool {
+	matched := false
+	for _, secret := range s.secrets {
+		expected := computeSignature(secret, timestamp, nonce, method, path, body)
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				matched = true
+			}
+		}
+	}
+	return matched
+}
+
+//computeSignature returns the HMAC-SHA256 of the signed payload
+func computeSignature(secret []byte, timestamp int64, nonce string, method string, path string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + nonce + "\n" + strings.ToUpper(method) + "\n" + path + "\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+//readAndRestoreBody reads the request body and replaces it so that it can be read again
+func readAndRestoreBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.Body.Close()
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
@@ -0,0 +1,174 @@
+package crypto_utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestSignerWithoutSecret(t *testing.T) {
+	//act
+	signer, err := NewRequestSigner("", " ")
+
+	//assert
+	assert.Nil(t, signer)
+	assert.Equal(t, ErrNoSigningSecret, err)
+}
+
+func TestRequestSignerSignAndVerifySuccessful(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+	body := []byte(`{"event":"created"}`)
+
+	//act
+	header, _ := signer.Sign("POST", "/webhooks", body)
+	err := signer.Verify(header, "POST", "/webhooks", body)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(header, "t="))
+	assert.Contains(t, header, ",v1=")
+}
+
+func TestRequestSignerVerifyTamperedParts(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+	header, _ := signer.Sign("POST", "/webhooks", []byte("body"))
+
+	//act & assert
+	assert.Equal(t, ErrSignatureMismatch, signer.Verify(header, "POST", "/webhooks", []byte("other")))
+	assert.Equal(t, ErrSignatureMismatch, signer.Verify(header, "PUT", "/webhooks", []byte("body")))
+	assert.Equal(t, ErrSignatureMismatch, signer.Verify(header, "POST", "/other", []byte("body")))
+}
+
+func TestRequestSignerVerifyWrongSecret(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+	other, _ := NewRequestSigner("other")
+	header, _ := other.Sign("POST", "/webhooks", nil)
+
+	//act
+	err := signer.Verify(header, "POST", "/webhooks", nil)
+
+	//assert
+	assert.Equal(t, ErrSignatureMismatch, err)
+}
+
+func TestRequestSignerVerifyMalformedHeader(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+
+	//act & assert
+	assert.Equal(t, ErrMissingSignature, signer.Verify("", "POST", "/", nil))
+	assert.Equal(t, ErrMalformedSignature, signer.Verify("garbage", "POST", "/", nil))
+	assert.Equal(t, ErrMalformedSignature, signer.Verify("t=abc,v1=00", "POST", "/", nil))
+	assert.Equal(t, ErrMalformedSignature, signer.Verify("t=1600000000,v1=zz", "POST", "/", nil))
+	assert.Equal(t, ErrMalformedSignature, signer.Verify("t=1600000000", "POST", "/", nil))
+}
+
+func TestRequestSignerVerifyOutsideTolerance(t *testing.T) {
+	//arrange
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer, _ := NewRequestSigner("secret")
+	signer.SetTolerance(time.Minute)
+	clock := date_utils.NewFakeClock(now)
+	signer.SetClock(clock)
+	header, _ := signer.Sign("POST", "/webhooks", nil)
+
+	//act
+	clock.Set(now.Add(59 * time.Second))
+	withinErr := signer.Verify(header, "POST", "/webhooks", nil)
+	clock.Set(now.Add(2 * time.Minute))
+	lateErr := signer.Verify(header, "POST", "/webhooks", nil)
+	clock.Set(now.Add(-2 * time.Minute))
+	earlyErr := signer.Verify(header, "POST", "/webhooks", nil)
+
+	//assert
+	assert.Nil(t, withinErr)
+	assert.Equal(t, ErrSignatureExpired, lateErr)
+	assert.Equal(t, ErrSignatureExpired, earlyErr)
+}
+
+func TestRequestSignerVerifyReplayRejected(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+	signer.SetNonceCache(NewMemoryNonceCache())
+	header, _ := signer.Sign("POST", "/webhooks", nil)
+
+	//act
+	firstErr := signer.Verify(header, "POST", "/webhooks", nil)
+	replayErr := signer.Verify(header, "POST", "/webhooks", nil)
+	otherHeader, _ := signer.Sign("POST", "/webhooks", nil)
+	otherErr := signer.Verify(otherHeader, "POST", "/webhooks", nil)
+
+	//assert
+	assert.Nil(t, firstErr)
+	assert.Equal(t, ErrSignatureReplayed, replayErr)
+	assert.Nil(t, otherErr)
+}
+
+func TestRequestSignerRotationSuccessful(t *testing.T) {
+	//arrange
+	oldSigner, _ := NewRequestSigner("old")
+	rotatingSigner, _ := NewRequestSigner("new", "old")
+	newSigner, _ := NewRequestSigner("new")
+
+	//act
+	oldHeader, _ := oldSigner.Sign("POST", "/webhooks", nil)
+	rotatingHeader, _ := rotatingSigner.Sign("POST", "/webhooks", nil)
+
+	//assert
+	assert.Nil(t, rotatingSigner.Verify(oldHeader, "POST", "/webhooks", nil))
+	assert.Nil(t, oldSigner.Verify(rotatingHeader, "POST", "/webhooks", nil))
+	assert.Nil(t, newSigner.Verify(rotatingHeader, "POST", "/webhooks", nil))
+	assert.Equal(t, ErrSignatureMismatch, newSigner.Verify(oldHeader, "POST", "/webhooks", nil))
+}
+
+func TestRequestSignerSignAndVerifyRequestSuccessful(t *testing.T) {
+	//arrange
+	signer, _ := NewRequestSigner("secret")
+	request, _ := http.NewRequest("POST", "https://example.com/webhooks?id=1", bytes.NewBufferString(`{"event":"created"}`))
+
+	//act
+	signErr := signer.SignRequest(request)
+	verifyErr := signer.VerifyRequest(request)
+	body, _ := ioutil.ReadAll(request.Body)
+
+	//assert
+	assert.Nil(t, signErr)
+	assert.Nil(t, verifyErr)
+	assert.NotEmpty(t, request.Header.Get(SignatureHeader))
+	assert.Equal(t, `{"event":"created"}`, string(body))
+}
+
+func TestMemoryNonceCacheEvictsExpired(t *testing.T) {
+	//arrange
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewMemoryNonceCache()
+	clock := date_utils.NewFakeClock(now)
+	cache.SetClock(clock)
+
+	//act
+	first := cache.Add("nonce", now.Add(time.Minute))
+	duplicate := cache.Add("nonce", now.Add(time.Minute))
+	clock.Advance(2 * time.Minute)
+	afterExpiry := cache.Add("nonce", now.Add(3*time.Minute))
+	other := cache.Add("other", now.Add(4*time.Minute))
+	clock.Advance(90 * time.Second)
+	cache.Add("last", now.Add(5*time.Minute))
+
+	//assert
+	assert.True(t, first)
+	assert.False(t, duplicate)
+	assert.True(t, afterExpiry)
+	assert.True(t, other)
+	// the re-added nonce expired at 3 minutes and was evicted, other and last remain
+	assert.Equal(t, 2, cache.Len())
+	assert.False(t, cache.Add("other", now.Add(4*time.Minute)))
+}
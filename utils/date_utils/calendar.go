@@ -0,0 +1,281 @@
+package date_utils
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+//ErrNoWorkingDay returned by SetWeekend when every day would be a weekend day
+var ErrNoWorkingDay = errors.New("date_utils: the weekend cannot cover every day")
+
+//Holiday non working day of a Calendar
+type Holiday struct {
+	Name string
+	// Date midnight in the calendar location
+	Date time.Time
+	// Observed true for the day making up for a holiday falling on a weekend
+	Observed bool
+}
+
+//Calendar working day arithmetic in a timezone, with configurable weekends and holidays. Dates are compared in the
+//calendar location and results are time.Time values in that location, keeping the time of day of the input, so
+//they can be formatted with ApiDateTimeFormat, or converted to UTC for DbDateTimeFormat.
+type Calendar struct {
+	mu       sync.RWMutex
+	location *time.Location
+	weekend  [7]bool
+	rules    []HolidayRule
+	// holidays by date key, of the rules of each year, built on first use
+	years map[int]map[int]Holiday
+}
+
+//NewCalendar constructor for a calendar in the location, UTC when nil, with Saturday and Sunday as weekend
+func NewCalendar(location *time.Location, rules ...HolidayRule) *Calendar {
+	if location == nil {
+		location = time.UTC
+	}
+
+	c := &Calendar{
+		location: location,
+		rules:    rules,
+		years:    map[int]map[int]Holiday{},
+	}
+	c.weekend[time.Saturday] = true
+	c.weekend[time.Sunday] = true
+	return c
+}
+
+//NewCalendarInZone constructor for a calendar in an IANA timezone such as Europe/Malta
+func NewCalendarInZone(zone string, rules ...HolidayRule) (*Calendar, error) {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, err
+	}
+	return NewCalendar(location, rules...), nil
+}
+
+//Location returns the timezone of the calendar
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+//SetWeekend replaces the weekend days, such as Friday and Saturday
+func (c *Calendar) SetWeekend(days ...time.Weekday) error {
+	var weekend [7]bool
+	count := 0
+	for _, day := range days {
+		if !weekend[day] {
+			weekend[day] = true
+			count++
+		}
+	}
+	if count == len(weekend) {
+		return ErrNoWorkingDay
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.weekend = weekend
+	// observed holidays depend on the weekend
+	c.years = map[int]map[int]Holiday{}
+	return nil
+}
+
+//AddHolidays adds holiday rules
+func (c *Calendar) AddHolidays(rules ...HolidayRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rules = append(c.rules, rules...)
+	c.years = map[int]map[int]Holiday{}
+}
+
+//LoadHolidays adds the holiday rules of a file, see ParseHolidayRules for the format
+func (c *Calendar) LoadHolidays(path string) error {
+	rules, err := LoadHolidayRules(path)
+	if err != nil {
+		return err
+	}
+	c.AddHolidays(rules...)
+	return nil
+}
+
+//Holidays returns the holidays falling in the year, observed days included, sorted by date
+func (c *Calendar) Holidays(year int) []Holiday {
+	var holidays []Holiday
+	seen := map[int]bool{}
+	// observed days can cross the new year
+	for y := year - 1; y <= year+1; y++ {
+		for key, holiday := range c.holidaysOf(y) {
+			if holiday.Date.Year() == year && !seen[key] {
+				seen[key] = true
+				holidays = append(holidays, holiday)
+			}
+		}
+	}
+
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+//IsHoliday returns the holiday on the date of t in the calendar location
+func (c *Calendar) IsHoliday(t time.Time) (Holiday, bool) {
+	t = t.In(c.location)
+	key := dateKey(t)
+	for y := t.Year() - 1; y <= t.Year()+1; y++ {
+		if holiday, ok := c.holidaysOf(y)[key]; ok {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+//IsWeekend checks if the date of t in the calendar location is a weekend day
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.weekend[t.In(c.location).Weekday()]
+}
+
+//IsBusinessDay checks if the date of t in the calendar location is neither a weekend day nor a holiday
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c.IsWeekend(t) {
+		return false
+	}
+	_, holiday := c.IsHoliday(t)
+	return !holiday
+}
+
+//NextBusinessDay returns the first business day after the date of t, at the same time of day
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, 1)
+}
+
+//PreviousBusinessDay returns the last business day before the date of t, at the same time of day
+func (c *Calendar) PreviousBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, -1)
+}
+
+//AddBusinessDays moves t by n business days, backwards when n is negative, keeping the time of day.
+//Days are counted from the date of t whether or not it is a business day, and n 0 returns t unchanged.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	t = t.In(c.location)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+//BusinessDaysBetween counts the business days from the date of from, included, to the date of to, excluded.
+//The count is negative when to is before from.
+func (c *Calendar) BusinessDaysBetween(from time.Time, to time.Time) int {
+	from, to = c.startOfDay(from), c.startOfDay(to)
+	sign := 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+
+	count := 0
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(day) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+//startOfDay returns midnight of the date of t in the calendar location
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+}
+
+//holidaysOf returns the holidays produced by the rules of the year, building them on first use
+func (c *Calendar) holidaysOf(year int) map[int]Holiday {
+	c.mu.RLock()
+	holidays, ok := c.years[year]
+	c.mu.RUnlock()
+	if ok {
+		return holidays
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if holidays, ok = c.years[year]; ok {
+		return holidays
+	}
+
+	holidays = map[int]Holiday{}
+	type observable struct {
+		holiday Holiday
+		rule    ObservedRule
+	}
+	var weekendHolidays []observable
+	for _, rule := range c.rules {
+		date, ok := rule.dateIn(year, c.location)
+		if !ok {
+			continue
+		}
+
+		holiday := Holiday{Name: rule.Name, Date: date}
+		if _, exists := holidays[dateKey(date)]; !exists {
+			holidays[dateKey(date)] = holiday
+		}
+		if rule.Observed != ObservedNone && c.weekend[date.Weekday()] {
+			weekendHolidays = append(weekendHolidays, observable{holiday: holiday, rule: rule.Observed})
+		}
+	}
+
+	// made up days are assigned in date order, so that Boxing Day moves past the day observed for Christmas
+	sort.SliceStable(weekendHolidays, func(i, j int) bool {
+		return weekendHolidays[i].holiday.Date.Before(weekendHolidays[j].holiday.Date)
+	})
+	for _, w := range weekendHolidays {
+		date := c.observedDate(w.holiday.Date, w.rule, holidays)
+		if _, exists := holidays[dateKey(date)]; !exists {
+			holidays[dateKey(date)] = Holiday{Name: w.holiday.Name, Date: date, Observed: true}
+		}
+	}
+
+	c.years[year] = holidays
+	return holidays
+}
+
+//observedDate returns the day making up for a holiday on a weekend, called with the lock held
+func (c *Calendar) observedDate(date time.Time, rule ObservedRule, holidays map[int]Holiday) time.Time {
+	switch rule {
+	case ObservedNextMonday:
+		return date.AddDate(0, 0, (int(time.Monday)-int(date.Weekday())+7)%7)
+	case ObservedNearestWeekday:
+		if previous := date.AddDate(0, 0, -1); !c.weekend[previous.Weekday()] {
+			return previous
+		}
+		for date = date.AddDate(0, 0, 1); c.weekend[date.Weekday()]; date = date.AddDate(0, 0, 1) {
+		}
+		return date
+	}
+
+	for date = date.AddDate(0, 0, 1); ; date = date.AddDate(0, 0, 1) {
+		if _, holiday := holidays[dateKey(date)]; !holiday && !c.weekend[date.Weekday()] {
+			return date
+		}
+	}
+}
+
+//dateKey identifies the calendar date of t as yyyymmdd
+func dateKey(t time.Time) int {
+	return t.Year()*10000 + int(t.Month())*100 + t.Day()
+}
@@ -0,0 +1,230 @@
+package date_utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const maltaHolidays = `# Malta public holidays, which are not made up when on a weekend
+01-01 New Year's Day
+02-10 St Paul's Shipwreck
+03-19 St Joseph
+03-31 Freedom Day
+easter-2 Good Friday
+05-01 Workers' Day
+06-07 Sette Giugno
+06-29 St Peter and St Paul
+08-15 Assumption
+09-08 Victory Day
+09-21 Independence Day
+12-08 Immaculate Conception
+12-13 Republic Day
+12-25 Christmas Day
+`
+
+func newMaltaCalendar(t *testing.T) *Calendar {
+	path := filepath.Join(t.TempDir(), "malta.holidays")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(maltaHolidays), 0600))
+
+	calendar, err := NewCalendarInZone("Europe/Malta")
+	assert.Nil(t, err)
+	assert.Nil(t, calendar.LoadHolidays(path))
+	return calendar
+}
+
+func TestEasterSuccessful(t *testing.T) {
+	expected := map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	}
+
+	for year, date := range expected {
+		// act
+		easter := Easter(year, time.UTC)
+
+		// assert
+		assert.Equal(t, date, easter.Format("2006-01-02"))
+	}
+}
+
+func TestCalendarAddBusinessDaysSuccessful(t *testing.T) {
+	// arrange
+	calendar := newMaltaCalendar(t)
+	// Thursday before Good Friday, Easter Sunday 2024 also being Freedom Day
+	start := time.Date(2024, 3, 28, 10, 0, 0, 0, calendar.Location())
+
+	// act
+	result := calendar.AddBusinessDays(start, 3)
+	back := calendar.AddBusinessDays(result, -3)
+
+	// assert
+	assert.Equal(t, "2024-04-03T10:00:00+02:00", ConvertToApiDateFormat(&result))
+	utc := result.UTC()
+	assert.Equal(t, "2024-04-03 08:00:00", ConvertToDbDateFormat(&utc))
+	assert.Equal(t, start, back)
+	assert.Equal(t, start, calendar.AddBusinessDays(start, 0))
+}
+
+func TestCalendarUsesLocationDate(t *testing.T) {
+	// arrange
+	calendar := newMaltaCalendar(t)
+	// 23:30 UTC on December 24 is already Christmas Day in Malta
+	christmasEve := time.Date(2024, 12, 24, 23, 30, 0, 0, time.UTC)
+
+	// act
+	holiday, ok := calendar.IsHoliday(christmasEve)
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "Christmas Day", holiday.Name)
+	assert.False(t, calendar.IsBusinessDay(christmasEve))
+}
+
+func TestCalendarNextAndPreviousBusinessDay(t *testing.T) {
+	// arrange
+	calendar := newMaltaCalendar(t)
+	easterMonday := time.Date(2024, 4, 1, 9, 0, 0, 0, calendar.Location())
+	saturday := time.Date(2024, 4, 6, 9, 0, 0, 0, calendar.Location())
+
+	// act
+	previous := calendar.PreviousBusinessDay(easterMonday)
+	next := calendar.NextBusinessDay(saturday)
+
+	// assert
+	assert.Equal(t, time.Date(2024, 3, 28, 9, 0, 0, 0, calendar.Location()), previous)
+	assert.Equal(t, time.Date(2024, 4, 8, 9, 0, 0, 0, calendar.Location()), next)
+}
+
+func TestCalendarBusinessDaysBetween(t *testing.T) {
+	// arrange
+	calendar := newMaltaCalendar(t)
+	from := time.Date(2024, 3, 25, 17, 0, 0, 0, calendar.Location())
+	to := time.Date(2024, 4, 5, 8, 0, 0, 0, calendar.Location())
+
+	// act
+	count := calendar.BusinessDaysBetween(from, to)
+	reverse := calendar.BusinessDaysBetween(to, from)
+	sameDay := calendar.BusinessDaysBetween(from, from)
+
+	// assert
+	assert.Equal(t, 8, count)
+	assert.Equal(t, -8, reverse)
+	assert.Equal(t, 0, sameDay)
+}
+
+func TestCalendarObservedNextWorkingDay(t *testing.T) {
+	// arrange
+	rules, err := ParseHolidayRules(strings.NewReader(`
+12-25 observed=next-working-day Christmas Day
+12-26 observed=next-working-day Boxing Day
+05-mon-1 Early May bank holiday
+05-mon-last Spring bank holiday
+`))
+	assert.Nil(t, err)
+	calendar := NewCalendar(nil, rules...)
+
+	// act
+	holidays := calendar.Holidays(2021)
+
+	// assert
+	var dates []string
+	for _, holiday := range holidays {
+		dates = append(dates, holiday.Date.Format("01-02"))
+	}
+	assert.Equal(t, []string{"05-03", "05-31", "12-25", "12-26", "12-27", "12-28"}, dates)
+	assert.True(t, holidays[4].Observed)
+	assert.Equal(t, "Christmas Day", holidays[4].Name)
+	assert.Equal(t, "Boxing Day", holidays[5].Name)
+}
+
+func TestCalendarObservedNearestWeekday(t *testing.T) {
+	// arrange
+	calendar := NewCalendar(time.UTC,
+		HolidayRule{Name: "New Year's Day", Kind: HolidayFixed, Month: time.January, Day: 1, Observed: ObservedNearestWeekday},
+		HolidayRule{Name: "Independence Day", Kind: HolidayFixed, Month: time.July, Day: 4, Observed: ObservedNearestWeekday},
+	)
+
+	// act
+	saturday, saturdayOk := calendar.IsHoliday(time.Date(2020, 7, 3, 0, 0, 0, 0, time.UTC))
+	sunday, sundayOk := calendar.IsHoliday(time.Date(2021, 7, 5, 0, 0, 0, 0, time.UTC))
+	// January 1 2022 is a Saturday, observed on the last day of 2021
+	newYear, newYearOk := calendar.IsHoliday(time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	holidays2021 := calendar.Holidays(2021)
+
+	// assert
+	assert.True(t, saturdayOk)
+	assert.True(t, saturday.Observed)
+	assert.True(t, sundayOk)
+	assert.Equal(t, "Independence Day", sunday.Name)
+	assert.True(t, newYearOk)
+	assert.Equal(t, "New Year's Day", newYear.Name)
+	assert.Equal(t, 4, len(holidays2021))
+}
+
+func TestCalendarSetWeekend(t *testing.T) {
+	// arrange
+	calendar := NewCalendar(time.UTC)
+	friday := time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)
+
+	// act
+	err := calendar.SetWeekend(time.Friday, time.Saturday)
+	allErr := calendar.SetWeekend(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, ErrNoWorkingDay, allErr)
+	assert.True(t, calendar.IsWeekend(friday))
+	assert.True(t, calendar.IsBusinessDay(friday.AddDate(0, 0, 2)))
+	assert.Equal(t, time.Date(2024, 4, 7, 12, 0, 0, 0, time.UTC), calendar.NextBusinessDay(friday))
+}
+
+func TestParseHolidayRulesSuccessful(t *testing.T) {
+	// act
+	rules, err := ParseHolidayRules(strings.NewReader(`
+02-29 Leap Day
+2024-06-07 from=2000 Election Day
+easter+1 from=2010 to=2030 observed=none Easter Monday
+11-thu-4 Thanksgiving
+`))
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []HolidayRule{
+		FixedHoliday("Leap Day", time.February, 29),
+		{Name: "Election Day", Kind: HolidayFixed, Month: time.June, Day: 7, FromYear: 2000, ToYear: 2024},
+		{Name: "Easter Monday", Kind: HolidayEaster, EasterOffset: 1, FromYear: 2010, ToYear: 2030},
+		NthWeekdayHoliday("Thanksgiving", time.November, time.Thursday, 4),
+	}, rules)
+
+	calendar := NewCalendar(time.UTC, rules...)
+	assert.Equal(t, 4, len(calendar.Holidays(2024)))
+	assert.Equal(t, 3, len(calendar.Holidays(2023)))
+	assert.Equal(t, 1, len(calendar.Holidays(2031)))
+}
+
+func TestParseHolidayRulesInvalid(t *testing.T) {
+	lines := []string{
+		"13-01 Invalid Month",
+		"easter+x Invalid Offset",
+		"05-mon-6 Invalid Week",
+		"12-25 observed=sometimes Christmas",
+		"12-25 colour=red Christmas",
+		"12-25",
+	}
+
+	for _, line := range lines {
+		// act
+		_, err := ParseHolidayRules(strings.NewReader("# comment\n" + line))
+
+		// assert
+		assert.NotNil(t, err, line)
+		assert.True(t, strings.HasPrefix(err.Error(), "date_utils: line 2: "), line)
+	}
+}
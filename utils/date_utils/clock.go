@@ -0,0 +1,125 @@
+package date_utils
+
+import (
+	"sync"
+	"time"
+)
+
+//Clock source of the current time, timers and tickers. Services take a Clock so that tests can use a FakeClock
+//instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+//Timer single event, see time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+//Ticker repeating event, see time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+var (
+	clockMu      sync.RWMutex
+	currentClock Clock = RealClock{}
+)
+
+//SetClock replaces the clock used by GetCurrentDateTime and the functions built on it, nil restores RealClock
+func SetClock(clock Clock) {
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	currentClock = clock
+}
+
+//GetClock returns the clock used by GetCurrentDateTime
+func GetClock() Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return currentClock
+}
+
+//RealClock Clock backed by the time package
+type RealClock struct{}
+
+//Now returns time.Now
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+//Since returns time.Since
+func (RealClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+//Sleep calls time.Sleep
+func (RealClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+//After returns time.After
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+//AfterFunc wraps time.AfterFunc
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &realTimer{timer: time.AfterFunc(d, f)}
+}
+
+//NewTimer wraps time.NewTimer
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+//NewTicker wraps time.NewTicker
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t *realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t *realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t *realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t *realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+func (t *realTicker) Reset(d time.Duration) {
+	t.ticker.Reset(d)
+}
@@ -0,0 +1,168 @@
+package date_utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fakeStart = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFakeClockAdvanceSuccessful(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+
+	// act
+	clock.Advance(90 * time.Second)
+
+	// assert
+	assert.Equal(t, fakeStart.Add(90*time.Second), clock.Now())
+	assert.Equal(t, 90*time.Second, clock.Since(fakeStart))
+}
+
+func TestFakeClockTimerSuccessful(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+	timer := clock.NewTimer(time.Minute)
+	stopped := clock.NewTimer(time.Minute)
+
+	// act
+	clock.Advance(59 * time.Second)
+	firedEarly := len(timer.C()) > 0
+	wasActive := stopped.Stop()
+	clock.Advance(time.Second)
+
+	// assert
+	assert.False(t, firedEarly)
+	assert.True(t, wasActive)
+	assert.Equal(t, fakeStart.Add(time.Minute), <-timer.C())
+	assert.Len(t, stopped.C(), 0)
+	assert.False(t, timer.Stop())
+	assert.Equal(t, 0, clock.Waiters())
+}
+
+func TestFakeClockTimerReset(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+	timer := clock.NewTimer(time.Minute)
+
+	// act
+	clock.Advance(30 * time.Second)
+	active := timer.Reset(time.Minute)
+	clock.Advance(45 * time.Second)
+	firedEarly := len(timer.C()) > 0
+	clock.Advance(15 * time.Second)
+
+	// assert
+	assert.True(t, active)
+	assert.False(t, firedEarly)
+	assert.Equal(t, fakeStart.Add(90*time.Second), <-timer.C())
+}
+
+func TestFakeClockTickerSuccessful(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+	ticker := clock.NewTicker(10 * time.Second)
+	var ticks []time.Time
+
+	// act
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Second)
+		ticks = append(ticks, <-ticker.C())
+	}
+	// unread ticks are dropped
+	clock.Advance(time.Minute)
+	pending := len(ticker.C())
+	ticker.Stop()
+
+	// assert
+	assert.Equal(t, []time.Time{
+		fakeStart.Add(10 * time.Second),
+		fakeStart.Add(20 * time.Second),
+		fakeStart.Add(30 * time.Second),
+	}, ticks)
+	assert.Equal(t, 1, pending)
+	assert.Equal(t, 0, clock.Waiters())
+}
+
+func TestFakeClockAfterFuncOrder(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+	var order []string
+	var times []time.Time
+	clock.AfterFunc(2*time.Second, func() {
+		order = append(order, "second")
+		times = append(times, clock.Now())
+	})
+	clock.AfterFunc(time.Second, func() {
+		order = append(order, "first")
+		times = append(times, clock.Now())
+	})
+
+	// act
+	clock.Advance(time.Minute)
+
+	// assert
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, []time.Time{fakeStart.Add(time.Second), fakeStart.Add(2 * time.Second)}, times)
+	assert.Equal(t, fakeStart.Add(time.Minute), clock.Now())
+}
+
+func TestFakeClockSleepSuccessful(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+	done := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Hour)
+		done <- clock.Now()
+	}()
+
+	// act
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	// assert
+	assert.Equal(t, fakeStart.Add(time.Hour), <-done)
+}
+
+func TestFakeClockSleepNonPositiveReturnsImmediately(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(fakeStart)
+
+	// act
+	clock.Sleep(0)
+	clock.Sleep(-time.Second)
+
+	// assert
+	assert.Equal(t, 0, clock.Waiters())
+	assert.Equal(t, fakeStart, clock.Now())
+}
+
+func TestSetClockSuccessful(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(time.Date(2021, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	defer SetClock(nil)
+
+	// act
+	SetClock(clock)
+	apiNow := GetApiCurrentDateTimeString()
+	dbNow := GetDbCurrentDateTimeString()
+
+	// assert
+	assert.Equal(t, "2021-06-01T12:00:00Z", apiNow)
+	assert.Equal(t, "2021-06-01 12:00:00", dbNow)
+}
+
+func TestRealClockTimerSuccessful(t *testing.T) {
+	// arrange
+	clock := RealClock{}
+	start := clock.Now()
+
+	// act
+	fired := <-clock.NewTimer(time.Millisecond).C()
+
+	// assert
+	assert.False(t, fired.Before(start))
+	assert.True(t, clock.Since(start) >= time.Millisecond)
+}
@@ -9,8 +9,9 @@ const(
 	DbDateTimeFormat = "2006-01-02 15:04:05"
 )
 
+//GetCurrentDateTime returns the current UTC time of the clock set with SetClock
 func GetCurrentDateTime() time.Time{
-	return time.Now().UTC()
+	return GetClock().Now().UTC()
 }
 
 func GetApiCurrentDateTimeString() string {
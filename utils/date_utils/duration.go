@@ -0,0 +1,259 @@
+package date_utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidISODuration returned for values which are not ISO 8601 durations
+	ErrInvalidISODuration = errors.New("date_utils: invalid ISO 8601 duration")
+	// ErrNominalPeriod returned when converting a period with years or months to an exact duration
+	ErrNominalPeriod = errors.New("date_utils: years and months have no fixed duration")
+	// ErrMixedSignPeriod returned when formatting a period whose components have different signs
+	ErrMixedSignPeriod = errors.New("date_utils: periods with mixed signs are not representable in ISO 8601")
+)
+
+//Period ISO 8601 duration such as P1Y2M3DT4H5M6S. Years, months and days are calendar units applied with
+//time.AddDate, the rest is an exact duration.
+type Period struct {
+	Years  int
+	Months int
+	Days   int
+	Time   time.Duration
+}
+
+//ParsePeriod parses an ISO 8601 duration such as P1DT2H, P2W or -PT1.5S. Only the seconds may have a fraction,
+//with a dot or a comma.
+func ParsePeriod(value string) (Period, error) {
+	var p Period
+	value = strings.ToUpper(strings.TrimSpace(value))
+
+	negative := false
+	switch {
+	case strings.HasPrefix(value, "-"):
+		negative, value = true, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return Period{}, ErrInvalidISODuration
+	}
+	value = value[1:]
+
+	datePart, timePart := value, ""
+	if i := strings.IndexByte(value, 'T'); i >= 0 {
+		datePart, timePart = value[:i], value[i+1:]
+		if timePart == "" {
+			return Period{}, ErrInvalidISODuration
+		}
+	}
+
+	if err := parsePeriodComponents(datePart, "YMWD", func(unit byte, number string) error {
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return ErrInvalidISODuration
+		}
+		switch unit {
+		case 'Y':
+			p.Years = n
+		case 'M':
+			p.Months = n
+		case 'W':
+			if n > math.MaxInt/7 {
+				return ErrInvalidISODuration
+			}
+			p.Days += n * 7
+		case 'D':
+			if p.Days > math.MaxInt-n {
+				return ErrInvalidISODuration
+			}
+			p.Days += n
+		}
+		return nil
+	}); err != nil {
+		return Period{}, err
+	}
+
+	if err := parsePeriodComponents(timePart, "HMS", func(unit byte, number string) error {
+		var d time.Duration
+		if unit != 'S' {
+			n, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return ErrInvalidISODuration
+			}
+			size := time.Minute
+			if unit == 'H' {
+				size = time.Hour
+			}
+			if d, err = multiplyDuration(n, size); err != nil {
+				return err
+			}
+		} else {
+			seconds, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+			nanoseconds := math.Round(seconds * float64(time.Second))
+			// float64(math.MaxInt64) rounds up to 2^63, which no longer fits
+			if err != nil || math.IsNaN(seconds) || nanoseconds >= float64(math.MaxInt64) {
+				return ErrInvalidISODuration
+			}
+			d = time.Duration(nanoseconds)
+		}
+
+		var err error
+		p.Time, err = addDuration(p.Time, d)
+		return err
+	}); err != nil {
+		return Period{}, err
+	}
+
+	if negative {
+		p = p.Negate()
+	}
+	return p, nil
+}
+
+//parsePeriodComponents calls set for each number and unit, units having to follow the order of the allowed ones
+func parsePeriodComponents(value string, units string, set func(unit byte, number string) error) error {
+	for value != "" {
+		i := strings.IndexFunc(value, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if i <= 0 {
+			return ErrInvalidISODuration
+		}
+
+		unit := strings.IndexByte(units, value[i])
+		if unit < 0 {
+			return ErrInvalidISODuration
+		}
+		if err := set(value[i], value[:i]); err != nil {
+			return err
+		}
+		units, value = units[unit+1:], value[i+1:]
+	}
+	return nil
+}
+
+//Negate returns the period with every component negated
+func (p Period) Negate() Period {
+	return Period{Years: -p.Years, Months: -p.Months, Days: -p.Days, Time: -p.Time}
+}
+
+//AddTo returns t moved by the period, calendar units first
+func (p Period) AddTo(t time.Time) time.Time {
+	return t.AddDate(p.Years, p.Months, p.Days).Add(p.Time)
+}
+
+//Duration returns the exact duration of the period, counting days as 24 hours. Returns ErrNominalPeriod when
+//the period has years or months.
+func (p Period) Duration() (time.Duration, error) {
+	if p.Years != 0 || p.Months != 0 {
+		return 0, ErrNominalPeriod
+	}
+
+	days, err := multiplyDuration(int64(p.Days), 24*time.Hour)
+	if err != nil {
+		return 0, err
+	}
+	return addDuration(days, p.Time)
+}
+
+//multiplyDuration returns n times size, ErrInvalidISODuration when it does not fit in a time.Duration
+func multiplyDuration(n int64, size time.Duration) (time.Duration, error) {
+	if n > int64(math.MaxInt64/size) || n < int64(math.MinInt64/size) {
+		return 0, ErrInvalidISODuration
+	}
+	return time.Duration(n) * size, nil
+}
+
+//addDuration returns a plus b, ErrInvalidISODuration when it does not fit in a time.Duration
+func addDuration(a time.Duration, b time.Duration) (time.Duration, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrInvalidISODuration
+	}
+	return sum, nil
+}
+
+//String formats the period in ISO 8601, see Format. Periods with mixed signs, which are not representable,
+//are formatted like %!Period(1D,-1h0m0s) in the way of fmt bad verbs, so that they cannot be taken for ISO 8601.
+func (p Period) String() string {
+	formatted, err := p.Format()
+	if err != nil {
+		return fmt.Sprintf("%%!Period(%dY,%dM,%dD,%v)", p.Years, p.Months, p.Days, p.Time)
+	}
+	return formatted
+}
+
+//Format formats the period in ISO 8601, PT0S when empty. Years and months of different signs are normalised,
+//such as 1 year minus 1 month to P11M, other mixed signs return ErrMixedSignPeriod as ParsePeriod would not read
+//them back.
+func (p Period) Format() (string, error) {
+	if (p.Years > 0 && p.Months < 0) || (p.Years < 0 && p.Months > 0) {
+		months := p.Years*12 + p.Months
+		p.Years, p.Months = months/12, months%12
+	}
+
+	positive := p.Years > 0 || p.Months > 0 || p.Days > 0 || p.Time > 0
+	negative := p.Years < 0 || p.Months < 0 || p.Days < 0 || p.Time < 0
+	switch {
+	case positive && negative:
+		return "", ErrMixedSignPeriod
+	case negative:
+		if p.Time == math.MinInt64 {
+			// the only duration whose negation overflows
+			return "", ErrInvalidISODuration
+		}
+		formatted, err := p.Negate().Format()
+		return "-" + formatted, err
+	}
+
+	var b strings.Builder
+	b.WriteByte('P')
+	writeComponent(&b, int64(p.Years), 'Y')
+	writeComponent(&b, int64(p.Months), 'M')
+	writeComponent(&b, int64(p.Days), 'D')
+
+	if p.Time != 0 {
+		b.WriteByte('T')
+		writeComponent(&b, int64(p.Time/time.Hour), 'H')
+		writeComponent(&b, int64(p.Time%time.Hour/time.Minute), 'M')
+		if seconds := p.Time % time.Minute; seconds != 0 {
+			b.WriteString(strings.TrimRight(strings.TrimRight(strconv.FormatFloat(seconds.Seconds(), 'f', 9, 64), "0"), "."))
+			b.WriteByte('S')
+		}
+	}
+
+	if b.Len() == 1 {
+		return "PT0S", nil
+	}
+	return b.String(), nil
+}
+
+//writeComponent writes a non zero number followed by its unit
+func writeComponent(b *strings.Builder, n int64, unit byte) {
+	if n != 0 {
+		b.WriteString(strconv.FormatInt(n, 10))
+		b.WriteByte(unit)
+	}
+}
+
+//ParseISODuration parses an ISO 8601 duration into an exact duration, counting days as 24 hours.
+//Returns ErrNominalPeriod for durations with years or months, use ParsePeriod for those.
+func ParseISODuration(value string) (time.Duration, error) {
+	p, err := ParsePeriod(value)
+	if err != nil {
+		return 0, err
+	}
+	return p.Duration()
+}
+
+//FormatISODuration formats the duration in ISO 8601 such as P1DT2H, counting 24 hours as a day
+func FormatISODuration(d time.Duration) string {
+	day := 24 * time.Hour
+	return Period{Days: int(d / day), Time: d % day}.String()
+}
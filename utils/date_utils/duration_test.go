@@ -0,0 +1,109 @@
+package date_utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseISODurationSuccessful(t *testing.T) {
+	tests := map[string]time.Duration{
+		"P1DT2H":      26 * time.Hour,
+		"PT90M":       90 * time.Minute,
+		"PT1.5S":      1500 * time.Millisecond,
+		"PT0,25S":     250 * time.Millisecond,
+		"P2W":         14 * 24 * time.Hour,
+		"-PT30S":      -30 * time.Second,
+		"p1dt1h1m1s":  25*time.Hour + time.Minute + time.Second,
+		"PT0S":        0,
+		"P0D":         0,
+		" PT10H5M ":   10*time.Hour + 5*time.Minute,
+		"P1DT0.001S":  24*time.Hour + time.Millisecond,
+		"+P3DT12H30M": 84*time.Hour + 30*time.Minute,
+	}
+
+	for value, expected := range tests {
+		// act
+		d, err := ParseISODuration(value)
+
+		// assert
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, d, value)
+	}
+}
+
+func TestParseISODurationInvalid(t *testing.T) {
+	for _, value := range []string{"", "P", "PT", "1D", "P1H", "PT1D", "P1D2Y", "PT1.5H", "P-1D", "P1DT", "PXD", "P1.5.5D",
+		"PT10000000H", "PT2562047H60M", "PT9223372037S", "P106752DT1H", "PT2562047H47M17S", "P9223372036854775807W"} {
+		// act
+		_, err := ParseISODuration(value)
+
+		// assert
+		assert.Equal(t, ErrInvalidISODuration, err, value)
+	}
+}
+
+func TestParsePeriodCalendarUnits(t *testing.T) {
+	// arrange
+	start := time.Date(2021, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	// act
+	period, err := ParsePeriod("P1Y1M1DT1H")
+	_, durationErr := period.Duration()
+	_, isoErr := ParseISODuration("P1M")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, Period{Years: 1, Months: 1, Days: 1, Time: time.Hour}, period)
+	assert.Equal(t, time.Date(2022, 3, 4, 11, 0, 0, 0, time.UTC), period.AddTo(start))
+	assert.Equal(t, ErrNominalPeriod, durationErr)
+	assert.Equal(t, ErrNominalPeriod, isoErr)
+	assert.Equal(t, "P1Y1M1DT1H", period.String())
+	assert.Equal(t, "-P1Y1M1DT1H", period.Negate().String())
+}
+
+func TestFormatISODurationSuccessful(t *testing.T) {
+	tests := map[time.Duration]string{
+		26 * time.Hour:                  "P1DT2H",
+		0:                               "PT0S",
+		90 * time.Minute:                "PT1H30M",
+		1500 * time.Millisecond:         "PT1.5S",
+		-30 * time.Second:               "-PT30S",
+		48*time.Hour + time.Millisecond: "P2DT0.001S",
+	}
+
+	for d, expected := range tests {
+		// act
+		formatted := FormatISODuration(d)
+		parsed, err := ParseISODuration(formatted)
+
+		// assert
+		assert.Equal(t, expected, formatted)
+		assert.Nil(t, err)
+		assert.Equal(t, d, parsed)
+	}
+}
+
+func TestPeriodFormatMixedSigns(t *testing.T) {
+	// arrange
+	mixed := Period{Days: 1, Time: -time.Hour}
+	months := Period{Years: 1, Months: -1}
+	negativeMonths := Period{Years: -1, Months: 1, Days: -2}
+
+	// act
+	_, mixedErr := mixed.Format()
+	monthsFormatted, monthsErr := months.Format()
+	negativeFormatted, negativeErr := negativeMonths.Format()
+
+	// assert
+	assert.Equal(t, ErrMixedSignPeriod, mixedErr)
+	assert.Equal(t, "%!Period(0Y,0M,1D,-1h0m0s)", mixed.String())
+	assert.Nil(t, monthsErr)
+	assert.Equal(t, "P11M", monthsFormatted)
+	assert.Nil(t, negativeErr)
+	assert.Equal(t, "-P11M2D", negativeFormatted)
+	parsed, err := ParsePeriod(negativeFormatted)
+	assert.Nil(t, err)
+	assert.Equal(t, negativeMonths.AddTo(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)), parsed.AddTo(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,233 @@
+package date_utils
+
+import (
+	"sync"
+	"time"
+)
+
+//FakeClock Clock whose time only moves when told to. Timers, tickers and sleepers fire, in deadline order,
+//while Advance or Set moves the time past their deadline.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+//fakeWaiter timer, ticker or sleeper of a FakeClock
+type fakeWaiter struct {
+	clock    *FakeClock
+	c        chan time.Time
+	f        func()
+	deadline time.Time
+	// period of tickers, 0 for timers
+	period time.Duration
+}
+
+//NewFakeClock constructor, starting at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+//Now returns the fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+//Since returns the fake time elapsed since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+//Sleep blocks until the fake time is advanced by d, returning immediately when d is not positive like time.Sleep
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.After(d)
+}
+
+//After returns a channel receiving the fake time once it is advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+//AfterFunc calls f, in the goroutine advancing the clock, once the fake time is advanced by d
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &fakeTimer{c.addWaiter(d, 0, f)}
+}
+
+//NewTimer returns a timer firing once the fake time is advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return &fakeTimer{c.addWaiter(d, 0, nil)}
+}
+
+//NewTicker returns a ticker firing each time the fake time is advanced by d. Like time.NewTicker, d must be
+//positive and ticks are dropped when the channel is not read.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("date_utils: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{c.addWaiter(d, d, nil)}
+}
+
+//Advance moves the fake time forward by d, firing the timers and tickers whose deadline is reached
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+	c.Set(target)
+}
+
+//Set moves the fake time to t, firing the timers and tickers whose deadline is reached when moving forward
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	for {
+		next := c.nextWaiter(t)
+		if next == nil {
+			break
+		}
+
+		c.now = next.deadline
+		if next.period > 0 {
+			next.deadline = next.deadline.Add(next.period)
+		} else {
+			c.removeWaiter(next)
+		}
+
+		now := c.now
+		c.mu.Unlock()
+		next.fire(now)
+		c.mu.Lock()
+	}
+	c.now = t
+	c.mu.Unlock()
+}
+
+//Waiters returns the number of pending timers, tickers and sleepers
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+//BlockUntil waits until at least n timers, tickers or sleepers are pending, so that a test knows the goroutine
+//under test is waiting on the clock before advancing it
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+//addWaiter registers a timer or ticker
+func (c *FakeClock) addWaiter(d time.Duration, period time.Duration, f func()) *fakeWaiter {
+	w := &fakeWaiter{clock: c, f: f, period: period}
+	if f == nil {
+		w.c = make(chan time.Time, 1)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.deadline = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	return w
+}
+
+//nextWaiter returns the waiter with the earliest deadline not after t, nil when there is none
+func (c *FakeClock) nextWaiter(t time.Time) *fakeWaiter {
+	var next *fakeWaiter
+	for _, w := range c.waiters {
+		if !w.deadline.After(t) && (next == nil || w.deadline.Before(next.deadline)) {
+			next = w
+		}
+	}
+	return next
+}
+
+//removeWaiter unregisters the waiter, returning false when it was not pending
+func (c *FakeClock) removeWaiter(w *fakeWaiter) bool {
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+//fire calls the function or sends the time without blocking
+func (w *fakeWaiter) fire(now time.Time) {
+	if w.f != nil {
+		w.f()
+		return
+	}
+	select {
+	case w.c <- now:
+	default:
+	}
+}
+
+//reset reschedules the waiter d after the current fake time, returning false when it was not pending
+func (w *fakeWaiter) reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	active := w.clock.removeWaiter(w)
+	w.deadline = w.clock.now.Add(d)
+	w.clock.waiters = append(w.clock.waiters, w)
+	w.clock.cond.Broadcast()
+	return active
+}
+
+//stop unregisters the waiter, returning false when it was not pending
+func (w *fakeWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.removeWaiter(w)
+}
+
+type fakeTimer struct {
+	*fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.stop()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	return t.reset(d)
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.stop()
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("date_utils: non-positive interval for Ticker.Reset")
+	}
+	t.clock.mu.Lock()
+	t.period = d
+	t.clock.mu.Unlock()
+	t.reset(d)
+}
@@ -0,0 +1,280 @@
+package date_utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//HolidayKind how the date of a holiday is computed
+type HolidayKind int
+
+const (
+	// HolidayFixed same month and day every year, such as Christmas
+	HolidayFixed HolidayKind = iota
+	// HolidayEaster days relative to western Easter Sunday, such as Good Friday
+	HolidayEaster
+	// HolidayNthWeekday nth weekday of a month, such as the first Monday of May
+	HolidayNthWeekday
+)
+
+//ObservedRule how a holiday falling on a weekend is made up for
+type ObservedRule int
+
+const (
+	// ObservedNone the holiday is lost when it falls on a weekend
+	ObservedNone ObservedRule = iota
+	// ObservedNextMonday the following Monday is also a holiday
+	ObservedNextMonday
+	// ObservedNearestWeekday the working day before or after is also a holiday, Saturday moving to Friday and
+	// Sunday to Monday with the default weekend
+	ObservedNearestWeekday
+	// ObservedNextWorkingDay the next day which is neither a weekend nor another holiday is also a holiday,
+	// as with the UK substitute days for Christmas and Boxing Day
+	ObservedNextWorkingDay
+)
+
+var observedRuleNames = map[string]ObservedRule{
+	"none":             ObservedNone,
+	"next-monday":      ObservedNextMonday,
+	"nearest-weekday":  ObservedNearestWeekday,
+	"next-working-day": ObservedNextWorkingDay,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+//HolidayRule recurring or one-off holiday
+type HolidayRule struct {
+	Name string
+	Kind HolidayKind
+	// Month of fixed and nth weekday holidays
+	Month time.Month
+	// Day of the month of fixed holidays
+	Day int
+	// EasterOffset days after Easter Sunday of Easter holidays, negative for days before
+	EasterOffset int
+	// Weekday and Week of nth weekday holidays, Week -1 being the last one of the month
+	Weekday time.Weekday
+	Week    int
+	// FromYear and ToYear first and last years the holiday applies to, 0 for no bound
+	FromYear int
+	ToYear   int
+	Observed ObservedRule
+}
+
+//FixedHoliday rule for a holiday on the same month and day every year
+func FixedHoliday(name string, month time.Month, day int) HolidayRule {
+	return HolidayRule{Name: name, Kind: HolidayFixed, Month: month, Day: day}
+}
+
+//OneOffHoliday rule for a holiday on a single date, such as an election day
+func OneOffHoliday(name string, year int, month time.Month, day int) HolidayRule {
+	return HolidayRule{Name: name, Kind: HolidayFixed, Month: month, Day: day, FromYear: year, ToYear: year}
+}
+
+//EasterHoliday rule for a holiday offset days from western Easter Sunday, -2 being Good Friday
+func EasterHoliday(name string, offset int) HolidayRule {
+	return HolidayRule{Name: name, Kind: HolidayEaster, EasterOffset: offset}
+}
+
+//NthWeekdayHoliday rule for a holiday on the nth weekday of the month, -1 being the last one
+func NthWeekdayHoliday(name string, month time.Month, weekday time.Weekday, n int) HolidayRule {
+	return HolidayRule{Name: name, Kind: HolidayNthWeekday, Month: month, Weekday: weekday, Week: n}
+}
+
+//appliesTo checks if the rule is in effect during the year
+func (r HolidayRule) appliesTo(year int) bool {
+	return (r.FromYear == 0 || year >= r.FromYear) && (r.ToYear == 0 || year <= r.ToYear)
+}
+
+//dateIn returns the date of the holiday in the year, false when it does not exist that year
+func (r HolidayRule) dateIn(year int, location *time.Location) (time.Time, bool) {
+	if !r.appliesTo(year) {
+		return time.Time{}, false
+	}
+
+	switch r.Kind {
+	case HolidayFixed:
+		date := time.Date(year, r.Month, r.Day, 0, 0, 0, 0, location)
+		// February 29 only exists on leap years
+		return date, date.Month() == r.Month
+	case HolidayEaster:
+		return Easter(year, location).AddDate(0, 0, r.EasterOffset), true
+	case HolidayNthWeekday:
+		return nthWeekday(year, r.Month, r.Weekday, r.Week, location)
+	}
+	return time.Time{}, false
+}
+
+//Easter returns western Easter Sunday of the year, using the anonymous Gregorian algorithm
+func Easter(year int, location *time.Location) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, location)
+}
+
+//nthWeekday returns the nth weekday of the month, counting from the end when n is negative
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int, location *time.Location) (time.Time, bool) {
+	if n == 0 {
+		return time.Time{}, false
+	}
+
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, location)
+		date := first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+(n-1)*7)
+		return date, date.Month() == month
+	}
+
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, location)
+	date := last.AddDate(0, 0, -((int(last.Weekday())-int(weekday)+7)%7)+(n+1)*7)
+	return date, date.Month() == month
+}
+
+//ParseHolidayRules parses holiday rules, one per line, shaped "<date> [observed=<rule>] [from=<year>] [to=<year>] <name>".
+//The date is MM-DD for fixed holidays, YYYY-MM-DD for one-off holidays, easter, easter+N or easter-N for Easter
+//holidays and MM-<weekday>-<n> such as 05-mon-1 or 05-mon-last for nth weekday holidays. Observed rules are none,
+//next-monday, nearest-weekday and next-working-day. Blank lines and # comments are ignored.
+func ParseHolidayRules(reader io.Reader) ([]HolidayRule, error) {
+	var rules []HolidayRule
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule, err := parseHolidayRule(fields)
+		if err != nil {
+			return nil, fmt.Errorf("date_utils: line %d: %v", line, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+//LoadHolidayRules parses the holiday rules file at path, see ParseHolidayRules
+func LoadHolidayRules(path string) ([]HolidayRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseHolidayRules(file)
+}
+
+//parseHolidayRule parses the fields of a holiday rule line
+func parseHolidayRule(fields []string) (HolidayRule, error) {
+	rule, err := parseHolidayDate(strings.ToLower(fields[0]))
+	if err != nil {
+		return HolidayRule{}, err
+	}
+
+	i := 1
+	for ; i < len(fields); i++ {
+		key, value, ok := strings.Cut(fields[i], "=")
+		if !ok {
+			break
+		}
+
+		switch strings.ToLower(key) {
+		case "observed":
+			observed, ok := observedRuleNames[strings.ToLower(value)]
+			if !ok {
+				return HolidayRule{}, fmt.Errorf("unknown observed rule %q", value)
+			}
+			rule.Observed = observed
+		case "from":
+			if rule.FromYear, err = strconv.Atoi(value); err != nil {
+				return HolidayRule{}, fmt.Errorf("invalid from year %q", value)
+			}
+		case "to":
+			if rule.ToYear, err = strconv.Atoi(value); err != nil {
+				return HolidayRule{}, fmt.Errorf("invalid to year %q", value)
+			}
+		default:
+			return HolidayRule{}, fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	rule.Name = strings.Join(fields[i:], " ")
+	if rule.Name == "" {
+		return HolidayRule{}, fmt.Errorf("missing holiday name")
+	}
+	return rule, nil
+}
+
+//parseHolidayDate parses the date field of a holiday rule line
+func parseHolidayDate(value string) (HolidayRule, error) {
+	if strings.HasPrefix(value, "easter") {
+		offset := 0
+		if rest := strings.TrimPrefix(value, "easter"); rest != "" {
+			var err error
+			if offset, err = strconv.Atoi(rest); err != nil {
+				return HolidayRule{}, fmt.Errorf("invalid easter offset %q", value)
+			}
+		}
+		return EasterHoliday("", offset), nil
+	}
+
+	parts := strings.Split(value, "-")
+	switch len(parts) {
+	case 2:
+		date, err := time.Parse("01-02", value)
+		if err != nil {
+			// 02-29 does not parse without a year
+			if date, err = time.Parse("2006-01-02", "2000-"+value); err != nil {
+				return HolidayRule{}, fmt.Errorf("invalid date %q", value)
+			}
+		}
+		return FixedHoliday("", date.Month(), date.Day()), nil
+	case 3:
+		if weekday, ok := weekdayNames[parts[1]]; ok {
+			month, err := strconv.Atoi(parts[0])
+			if err != nil || month < 1 || month > 12 {
+				return HolidayRule{}, fmt.Errorf("invalid month %q", value)
+			}
+			n := -1
+			if parts[2] != "last" {
+				if n, err = strconv.Atoi(parts[2]); err != nil || n < 1 || n > 5 {
+					return HolidayRule{}, fmt.Errorf("invalid week %q", value)
+				}
+			}
+			return NthWeekdayHoliday("", time.Month(month), weekday, n), nil
+		}
+
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return HolidayRule{}, fmt.Errorf("invalid date %q", value)
+		}
+		return OneOffHoliday("", date.Year(), date.Month(), date.Day()), nil
+	}
+	return HolidayRule{}, fmt.Errorf("invalid date %q", value)
+}
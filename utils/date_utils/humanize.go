@@ -0,0 +1,129 @@
+package date_utils
+
+import (
+	"fmt"
+	"time"
+)
+
+//RelativeUnit unit of a humanized duration
+type RelativeUnit int
+
+const (
+	UnitSecond RelativeUnit = iota
+	UnitMinute
+	UnitHour
+	UnitDay
+	UnitWeek
+	UnitMonth
+	UnitYear
+)
+
+//Localizer words the output of a Humanizer, implemented per language as plural rules differ
+type Localizer interface {
+	// Now is used for differences under a minute, such as "just now"
+	Now() string
+	// Relative words n units in the past or the future, such as "3 hours ago" or "in 2 days"
+	Relative(n int, unit RelativeUnit, future bool) string
+}
+
+//EnglishLocalizer Localizer producing "3 hours ago" and "in 2 days"
+type EnglishLocalizer struct{}
+
+var englishUnits = map[RelativeUnit]string{
+	UnitSecond: "second",
+	UnitMinute: "minute",
+	UnitHour:   "hour",
+	UnitDay:    "day",
+	UnitWeek:   "week",
+	UnitMonth:  "month",
+	UnitYear:   "year",
+}
+
+//Now returns "just now"
+func (EnglishLocalizer) Now() string {
+	return "just now"
+}
+
+//Relative returns "n units ago" or "in n units"
+func (EnglishLocalizer) Relative(n int, unit RelativeUnit, future bool) string {
+	word := englishUnits[unit]
+	if n != 1 {
+		word += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, word)
+	}
+	return fmt.Sprintf("%d %s ago", n, word)
+}
+
+//Humanizer words the difference between two times, such as "3 hours ago" or "in 2 days"
+type Humanizer struct {
+	localizer Localizer
+	clock     Clock
+}
+
+//NewHumanizer constructor, the localizer defaults to EnglishLocalizer when nil
+func NewHumanizer(localizer Localizer) *Humanizer {
+	if localizer == nil {
+		localizer = EnglishLocalizer{}
+	}
+	return &Humanizer{localizer: localizer}
+}
+
+//SetClock replaces the clock used by FromNow, the clock set with SetClock by default
+func (h *Humanizer) SetClock(clock Clock) {
+	h.clock = clock
+}
+
+//Relative words t relative to now. Differences are rounded to the largest fitting unit: seconds under 45
+//seconds, minutes under 45 minutes, hours under 22 hours, days under a week, weeks under 30 days, months under
+//a year and years beyond.
+func (h *Humanizer) Relative(t time.Time, now time.Time) string {
+	d := t.Sub(now)
+	future := d > 0
+	if d < 0 {
+		d = -d
+	}
+
+	switch {
+	case d < time.Second:
+		return h.localizer.Now()
+	case d < 45*time.Second:
+		return h.localizer.Relative(round(d, time.Second), UnitSecond, future)
+	case d < 45*time.Minute:
+		return h.localizer.Relative(round(d, time.Minute), UnitMinute, future)
+	case d < 22*time.Hour:
+		return h.localizer.Relative(round(d, time.Hour), UnitHour, future)
+	}
+
+	day := 24 * time.Hour
+	switch days := round(d, day); {
+	case days < 7:
+		return h.localizer.Relative(days, UnitDay, future)
+	case days < 30:
+		return h.localizer.Relative(round(d, 7*day), UnitWeek, future)
+	case days < 365:
+		return h.localizer.Relative(round(d, 30*day), UnitMonth, future)
+	default:
+		return h.localizer.Relative(round(d, 365*day), UnitYear, future)
+	}
+}
+
+//FromNow words t relative to the current time of the clock
+func (h *Humanizer) FromNow(t time.Time) string {
+	clock := h.clock
+	if clock == nil {
+		clock = GetClock()
+	}
+	return h.Relative(t, clock.Now())
+}
+
+//Humanize words t relative to now in English, such as "3 hours ago" or "in 2 days"
+func Humanize(t time.Time, now time.Time) string {
+	return NewHumanizer(nil).Relative(t, now)
+}
+
+//round returns d in units, rounding halves up
+func round(d time.Duration, unit time.Duration) int {
+	return int((d + unit/2) / unit)
+}
@@ -0,0 +1,74 @@
+package date_utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//italianLocalizer Localizer used to check that the wording is pluggable
+type italianLocalizer struct{}
+
+func (italianLocalizer) Now() string {
+	return "adesso"
+}
+
+func (italianLocalizer) Relative(n int, unit RelativeUnit, future bool) string {
+	singular := []string{"secondo", "minuto", "ora", "giorno", "settimana", "mese", "anno"}
+	plural := []string{"secondi", "minuti", "ore", "giorni", "settimane", "mesi", "anni"}
+	word := plural[unit]
+	if n == 1 {
+		word = singular[unit]
+	}
+	if future {
+		return fmt.Sprintf("tra %d %s", n, word)
+	}
+	return fmt.Sprintf("%d %s fa", n, word)
+}
+
+func TestHumanizeSuccessful(t *testing.T) {
+	// arrange
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[time.Duration]string{
+		0:                        "just now",
+		-500 * time.Millisecond:  "just now",
+		-10 * time.Second:        "10 seconds ago",
+		-time.Minute:             "1 minute ago",
+		-3 * time.Hour:           "3 hours ago",
+		-150 * time.Minute:       "3 hours ago",
+		48 * time.Hour:           "in 2 days",
+		23 * time.Hour:           "in 1 day",
+		10 * 24 * time.Hour:      "in 1 week",
+		-60 * 24 * time.Hour:     "2 months ago",
+		3 * 365 * 24 * time.Hour: "in 3 years",
+	}
+
+	for offset, expected := range tests {
+		// act
+		output := Humanize(now.Add(offset), now)
+
+		// assert
+		assert.Equal(t, expected, output, offset.String())
+	}
+}
+
+func TestHumanizerLocalizedFromNow(t *testing.T) {
+	// arrange
+	clock := NewFakeClock(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	humanizer := NewHumanizer(italianLocalizer{})
+	humanizer.SetClock(clock)
+	event := clock.Now()
+
+	// act
+	now := humanizer.FromNow(event)
+	clock.Advance(time.Hour)
+	hourAgo := humanizer.FromNow(event)
+	future := humanizer.FromNow(clock.Now().Add(5 * 24 * time.Hour))
+
+	// assert
+	assert.Equal(t, "adesso", now)
+	assert.Equal(t, "1 ora fa", hourAgo)
+	assert.Equal(t, "tra 5 giorni", future)
+}
@@ -0,0 +1,118 @@
+package date_utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ErrInvalidDateTime returned when a value matches none of the layouts
+var ErrInvalidDateTime = errors.New("date_utils: unrecognised date time")
+
+// unix timestamps with at least this many digits are read as milliseconds, 10^11 seconds being in year 5138
+const unixMillisDigits = 12
+
+//DefaultLayouts layouts tried by ParseDateTime, in order. Layouts without a zone are read in the parser location.
+var DefaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	// PostgreSQL timestamptz output
+	"2006-01-02 15:04:05.999999999Z07",
+	DbDateTimeFormat,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	// ISO 8601 basic format
+	"20060102T150405.999999999Z0700",
+	"20060102T150405.999999999",
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+//DateTimeParser tolerant parser trying several layouts and unix timestamps
+type DateTimeParser struct {
+	location *time.Location
+	layouts  []string
+	unix     bool
+}
+
+//NewDateTimeParser constructor, values without a zone are read in location, UTC when nil, and results are returned
+//in location. The layouts default to DefaultLayouts.
+func NewDateTimeParser(location *time.Location, layouts ...string) *DateTimeParser {
+	if location == nil {
+		location = time.UTC
+	}
+	if len(layouts) == 0 {
+		layouts = DefaultLayouts
+	}
+
+	return &DateTimeParser{
+		location: location,
+		layouts:  layouts,
+		unix:     true,
+	}
+}
+
+//SetUnixTimestamps enables or disables reading digit only values as unix seconds or milliseconds, enabled by default
+func (p *DateTimeParser) SetUnixTimestamps(enabled bool) {
+	p.unix = enabled
+}
+
+//Parse reads the value with the first matching layout. Digit only values matching no layout, such as the
+//compact date 20240115 does, are unix seconds, or milliseconds from 12 digits on.
+func (p *DateTimeParser) Parse(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrInvalidDateTime
+	}
+
+	// RFC 3339 accepts lower case t and z, which only appear in the ISO layouts
+	normalized := value
+	if len(value) > 10 && value[4] == '-' || len(value) > 8 && value[8] == 't' {
+		normalized = strings.ToUpper(value)
+	}
+
+	for _, layout := range p.layouts {
+		if t, err := time.ParseInLocation(layout, normalized, p.location); err == nil {
+			return t.In(p.location), nil
+		}
+	}
+
+	if p.unix {
+		if t, ok := parseUnix(value); ok {
+			return t.In(p.location), nil
+		}
+	}
+	return time.Time{}, ErrInvalidDateTime
+}
+
+//ParseDateTime parses the value with DefaultLayouts or as a unix timestamp, reading values without a zone in
+//location, UTC when nil
+func ParseDateTime(value string, location *time.Location) (time.Time, error) {
+	return NewDateTimeParser(location).Parse(value)
+}
+
+//parseUnix reads an optionally signed digit only value as unix seconds or milliseconds
+func parseUnix(value string) (time.Time, bool) {
+	digits := strings.TrimPrefix(value, "-")
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(digits) >= unixMillisDigits {
+		return time.UnixMilli(n), true
+	}
+	return time.Unix(n, 0), true
+}
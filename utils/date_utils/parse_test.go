@@ -0,0 +1,96 @@
+package date_utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateTimeLayoutsSuccessful(t *testing.T) {
+	// arrange
+	expected := time.Date(2021, 6, 1, 12, 30, 15, 0, time.UTC)
+	values := []string{
+		"2021-06-01T12:30:15Z",
+		"2021-06-01t12:30:15z",
+		"2021-06-01T14:30:15+02:00",
+		"2021-06-01T12:30:15",
+		"2021-06-01 12:30:15",
+		"2021-06-01 14:30:15+02:00",
+		"  2021-06-01 12:30:15\n",
+		"1622550615",
+		"1622550615000",
+		"Tue, 01 Jun 2021 12:30:15 +0000",
+		"20210601T123015Z",
+		"20210601t143015+0200",
+		"20210601T123015",
+	}
+
+	for _, value := range values {
+		// act
+		parsed, err := ParseDateTime(value, nil)
+
+		// assert
+		assert.Nil(t, err, value)
+		assert.True(t, expected.Equal(parsed), value)
+		assert.Equal(t, time.UTC, parsed.Location(), value)
+	}
+}
+
+func TestParseDateTimeInLocation(t *testing.T) {
+	// arrange
+	malta, err := time.LoadLocation("Europe/Malta")
+	assert.Nil(t, err)
+
+	// act
+	dateOnly, dateErr := ParseDateTime("2021-06-01", malta)
+	withZone, zoneErr := ParseDateTime("2021-06-01T10:00:00Z", malta)
+	withFraction, fractionErr := ParseDateTime("2021-06-01T12:00:00.123", malta)
+
+	// assert
+	assert.Nil(t, dateErr)
+	assert.Equal(t, "2021-06-01T00:00:00+02:00", dateOnly.Format(ApiDateTimeFormat))
+	assert.Nil(t, zoneErr)
+	assert.Equal(t, "2021-06-01T12:00:00+02:00", withZone.Format(ApiDateTimeFormat))
+	assert.Nil(t, fractionErr)
+	assert.Equal(t, 123*time.Millisecond, time.Duration(withFraction.Nanosecond()))
+}
+
+func TestParseDateTimeCompactDateIsNotUnix(t *testing.T) {
+	// act
+	compact, err := ParseDateTime("20240115", nil)
+	unix, unixErr := ParseDateTime("20241315", nil)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), compact)
+	assert.Nil(t, unixErr)
+	assert.Equal(t, int64(20241315), unix.Unix())
+}
+
+func TestParseDateTimeInvalid(t *testing.T) {
+	for _, value := range []string{"", "yesterday", "2021-13-01", "2021-06-01T25:00:00Z", "12-", "--1"} {
+		// act
+		_, err := ParseDateTime(value, nil)
+
+		// assert
+		assert.Equal(t, ErrInvalidDateTime, err, value)
+	}
+}
+
+func TestDateTimeParserCustomLayouts(t *testing.T) {
+	// arrange
+	parser := NewDateTimeParser(time.UTC, "02/01/2006")
+	parser.SetUnixTimestamps(false)
+
+	// act
+	parsed, err := parser.Parse("01/06/2021")
+	_, isoErr := parser.Parse("2021-06-01")
+	_, unixErr := parser.Parse("1622550615")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), parsed)
+	assert.Equal(t, ErrInvalidDateTime, isoErr)
+	assert.Equal(t, ErrInvalidDateTime, unixErr)
+}
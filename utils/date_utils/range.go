@@ -0,0 +1,312 @@
+package date_utils
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidRange returned when the end of a range is before its start
+	ErrInvalidRange = errors.New("date_utils: range end is before its start")
+	// ErrInvalidRangeValue returned when scanning or unmarshalling a malformed range
+	ErrInvalidRangeValue = errors.New("date_utils: invalid range value")
+	// ErrUnboundedRange returned when scanning a range without a lower or upper bound, which Range cannot hold
+	ErrUnboundedRange = errors.New("date_utils: unbounded ranges are not supported")
+)
+
+// resolution of PostgreSQL timestamps, used to turn exclusive starts and inclusive ends into half-open bounds
+const rangeBoundResolution = time.Microsecond
+
+//Range half-open time range [Start, End), End being excluded so that consecutive ranges such as bookings or
+//subscription periods can share a bound. Stored in a single column as "[start,end)", or in two columns with
+//RangeColumns.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+//NewRange constructor, returning ErrInvalidRange when end is before start
+func NewRange(start time.Time, end time.Time) (Range, error) {
+	if end.Before(start) {
+		return Range{}, ErrInvalidRange
+	}
+	return Range{Start: start, End: end}, nil
+}
+
+//IsEmpty checks if the range contains no instant
+func (r Range) IsEmpty() bool {
+	return !r.Start.Before(r.End)
+}
+
+//Duration returns the length of the range
+func (r Range) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+//Contains checks if t is in the range, the end being excluded
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+//ContainsRange checks if other is within the range
+func (r Range) ContainsRange(other Range) bool {
+	return !other.Start.Before(r.Start) && !other.End.After(r.End)
+}
+
+//Overlaps checks if both ranges share at least an instant, ranges which only touch do not overlap
+func (r Range) Overlaps(other Range) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+//Intersection returns the instants shared by both ranges, false when they do not overlap
+func (r Range) Intersection(other Range) (Range, bool) {
+	if !r.Overlaps(other) {
+		return Range{}, false
+	}
+	return Range{Start: latest(r.Start, other.Start), End: earliest(r.End, other.End)}, true
+}
+
+//Union returns the range covering both ranges, false when they neither overlap nor touch
+func (r Range) Union(other Range) (Range, bool) {
+	if r.Start.After(other.End) || other.Start.After(r.End) {
+		return Range{}, false
+	}
+	return Range{Start: earliest(r.Start, other.Start), End: latest(r.End, other.End)}, true
+}
+
+//MergeRanges returns the ranges sorted by start, merging those which overlap or touch and dropping empty ones
+func MergeRanges(ranges []Range) []Range {
+	sorted := make([]Range, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var merged []Range
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 {
+			if union, ok := merged[last].Union(r); ok {
+				merged[last] = union
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+//SplitByDay splits the range at each midnight of the location, the location of Start when nil
+func (r Range) SplitByDay(location *time.Location) []Range {
+	return r.split(location, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	})
+}
+
+//SplitByWeek splits the range at each Monday midnight of the location, the location of Start when nil
+func (r Range) SplitByWeek(location *time.Location) []Range {
+	return r.split(location, func(t time.Time) time.Time {
+		days := (int(time.Monday) - int(t.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return time.Date(t.Year(), t.Month(), t.Day()+days, 0, 0, 0, 0, t.Location())
+	})
+}
+
+//SplitByMonth splits the range at the first day of each month of the location, the location of Start when nil
+func (r Range) SplitByMonth(location *time.Location) []Range {
+	return r.split(location, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	})
+}
+
+//split cuts the range at the boundaries returned by next, which gives the first boundary after t
+func (r Range) split(location *time.Location, next func(t time.Time) time.Time) []Range {
+	if r.IsEmpty() {
+		return nil
+	}
+	if location == nil {
+		location = r.Start.Location()
+	}
+
+	var parts []Range
+	start := r.Start.In(location)
+	end := r.End.In(location)
+	for start.Before(end) {
+		boundary := earliest(next(start), end)
+		parts = append(parts, Range{Start: start, End: boundary})
+		start = boundary
+	}
+	return parts
+}
+
+//Iterate calls fn with Start, Start plus step, Start plus twice the step and so on while before End, stopping
+//when fn returns false. Steps are computed from Start, so P1M from January 31 gives the end of each month.
+func (r Range) Iterate(step Period, fn func(t time.Time) bool) {
+	for n := 0; ; n++ {
+		t := r.Start.AddDate(step.Years*n, step.Months*n, step.Days*n).Add(step.Time * time.Duration(n))
+		if !t.Before(r.End) || (n > 0 && !t.After(r.Start)) || !fn(t) {
+			return
+		}
+	}
+}
+
+//String formats the range as [start,end) with RFC 3339 times
+func (r Range) String() string {
+	return fmt.Sprintf("[%s,%s)", r.Start.Format(time.RFC3339Nano), r.End.Format(time.RFC3339Nano))
+}
+
+//rangeJSON json shape of a Range
+type rangeJSON struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+//MarshalJSON encodes the range as {"start": "...", "end": "..."} with RFC 3339 times
+func (r Range) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rangeJSON{Start: r.Start.Format(time.RFC3339Nano), End: r.End.Format(time.RFC3339Nano)})
+}
+
+//UnmarshalJSON decodes {"start": "...", "end": "..."}, accepting any layout of ParseDateTime. null is a no-op.
+func (r *Range) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var value rangeJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := parseRange(value.Start, value.End)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+//Value stores the range as a "[start,end)" string, the zero range as "empty"
+func (r Range) Value() (driver.Value, error) {
+	if r == (Range{}) {
+		return "empty", nil
+	}
+	return r.String(), nil
+}
+
+//Scan reads a "[start,end)" string, including the quoted format of PostgreSQL tstzrange columns.
+//NULL and "empty" give the zero range. Exclusive starts and inclusive ends, such as "(start,end]", are moved by
+//a microsecond, the resolution of PostgreSQL timestamps. Unbounded and infinite bounds return ErrUnboundedRange.
+func (r *Range) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+		*r = Range{}
+		return nil
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("date_utils: cannot scan %T into a range", src)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "empty" {
+		*r = Range{}
+		return nil
+	}
+	if len(value) < 3 || !strings.ContainsRune("[(", rune(value[0])) || !strings.ContainsRune(")]", rune(value[len(value)-1])) {
+		return ErrInvalidRangeValue
+	}
+
+	bounds := strings.Split(value[1:len(value)-1], ",")
+	if len(bounds) != 2 {
+		return ErrInvalidRangeValue
+	}
+	start, end := strings.Trim(strings.TrimSpace(bounds[0]), `"`), strings.Trim(strings.TrimSpace(bounds[1]), `"`)
+	if isUnboundedRangeBound(start) || isUnboundedRangeBound(end) {
+		return ErrUnboundedRange
+	}
+
+	parsed, err := parseRange(start, end)
+	if err != nil {
+		return err
+	}
+	if value[0] == '(' {
+		parsed.Start = parsed.Start.Add(rangeBoundResolution)
+	}
+	if value[len(value)-1] == ']' {
+		parsed.End = parsed.End.Add(rangeBoundResolution)
+	}
+	if parsed.End.Before(parsed.Start) {
+		return ErrInvalidRange
+	}
+	*r = parsed
+	return nil
+}
+
+//isUnboundedRangeBound checks if the bound is missing or infinite
+func isUnboundedRangeBound(bound string) bool {
+	switch strings.ToLower(bound) {
+	case "", "infinity", "-infinity":
+		return true
+	}
+	return false
+}
+
+//RangeColumns two column storage of a Range, embedded in gorm models with gorm:"embedded;embedded_prefix:period_"
+//or scanned with rows.Scan(&columns.StartsAt, &columns.EndsAt)
+type RangeColumns struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+//Columns returns the range as two columns
+func (r Range) Columns() RangeColumns {
+	return RangeColumns{StartsAt: r.Start, EndsAt: r.End}
+}
+
+//Range returns the range stored in the columns
+func (c RangeColumns) Range() Range {
+	return Range{Start: c.StartsAt, End: c.EndsAt}
+}
+
+//parseRange parses both bounds and checks their order
+func parseRange(start string, end string) (Range, error) {
+	startTime, err := ParseDateTime(start, nil)
+	if err != nil {
+		return Range{}, ErrInvalidRangeValue
+	}
+	endTime, err := ParseDateTime(end, nil)
+	if err != nil {
+		return Range{}, ErrInvalidRangeValue
+	}
+	return NewRange(startTime, endTime)
+}
+
+//earliest returns the earlier of both times
+func earliest(a time.Time, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+//latest returns the later of both times
+func latest(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
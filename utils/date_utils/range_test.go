@@ -0,0 +1,220 @@
+package date_utils
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(d int, hour int) time.Time {
+	return time.Date(2021, 6, d, hour, 0, 0, 0, time.UTC)
+}
+
+func mustRange(t *testing.T, start time.Time, end time.Time) Range {
+	r, err := NewRange(start, end)
+	assert.Nil(t, err)
+	return r
+}
+
+func TestRangeContainsAndOverlaps(t *testing.T) {
+	// arrange
+	booking := mustRange(t, day(1, 14), day(3, 10))
+	next := mustRange(t, day(3, 10), day(5, 10))
+	overlapping := mustRange(t, day(2, 0), day(4, 0))
+
+	// act & assert
+	assert.True(t, booking.Contains(day(1, 14)))
+	assert.False(t, booking.Contains(day(3, 10)))
+	assert.False(t, booking.Overlaps(next))
+	assert.True(t, booking.Overlaps(overlapping))
+	assert.True(t, booking.ContainsRange(mustRange(t, day(2, 0), day(3, 10))))
+	assert.False(t, booking.ContainsRange(overlapping))
+	assert.Equal(t, 44*time.Hour, booking.Duration())
+	assert.True(t, mustRange(t, day(1, 0), day(1, 0)).IsEmpty())
+
+	_, err := NewRange(day(2, 0), day(1, 0))
+	assert.Equal(t, ErrInvalidRange, err)
+}
+
+func TestRangeIntersectionAndUnion(t *testing.T) {
+	// arrange
+	a := mustRange(t, day(1, 0), day(3, 0))
+	b := mustRange(t, day(2, 0), day(5, 0))
+	touching := mustRange(t, day(5, 0), day(6, 0))
+	apart := mustRange(t, day(10, 0), day(11, 0))
+
+	// act
+	intersection, intersects := a.Intersection(b)
+	_, touchingIntersects := b.Intersection(touching)
+	union, unites := b.Union(touching)
+	_, apartUnites := a.Union(apart)
+
+	// assert
+	assert.True(t, intersects)
+	assert.Equal(t, mustRange(t, day(2, 0), day(3, 0)), intersection)
+	assert.False(t, touchingIntersects)
+	assert.True(t, unites)
+	assert.Equal(t, mustRange(t, day(2, 0), day(6, 0)), union)
+	assert.False(t, apartUnites)
+}
+
+func TestMergeRangesSuccessful(t *testing.T) {
+	// arrange
+	ranges := []Range{
+		mustRange(t, day(10, 0), day(12, 0)),
+		mustRange(t, day(1, 0), day(3, 0)),
+		mustRange(t, day(2, 0), day(4, 0)),
+		mustRange(t, day(4, 0), day(5, 0)),
+		mustRange(t, day(7, 0), day(7, 0)),
+	}
+
+	// act
+	merged := MergeRanges(ranges)
+
+	// assert
+	assert.Equal(t, []Range{
+		mustRange(t, day(1, 0), day(5, 0)),
+		mustRange(t, day(10, 0), day(12, 0)),
+	}, merged)
+}
+
+func TestRangeSplitInLocation(t *testing.T) {
+	// arrange
+	malta, err := time.LoadLocation("Europe/Malta")
+	assert.Nil(t, err)
+	// 22:00 UTC is already midnight in Malta during summer time
+	r := mustRange(t, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), time.Date(2021, 6, 3, 6, 0, 0, 0, time.UTC))
+
+	// act
+	days := r.SplitByDay(malta)
+
+	// assert
+	assert.Len(t, days, 3)
+	assert.True(t, days[0].End.Equal(time.Date(2021, 6, 1, 22, 0, 0, 0, time.UTC)))
+	assert.True(t, days[1].End.Equal(time.Date(2021, 6, 2, 22, 0, 0, 0, time.UTC)))
+	assert.True(t, days[2].End.Equal(r.End))
+	assert.Equal(t, malta, days[1].Start.Location())
+}
+
+func TestRangeSplitByWeekAndMonth(t *testing.T) {
+	// arrange
+	// Wednesday June 2 to Wednesday July 14
+	r := mustRange(t, day(2, 9), time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC))
+
+	// act
+	weeks := r.SplitByWeek(nil)
+	months := r.SplitByMonth(nil)
+
+	// assert
+	assert.Len(t, weeks, 7)
+	assert.Equal(t, mustRange(t, day(2, 9), day(7, 0)), weeks[0])
+	assert.Equal(t, mustRange(t, day(7, 0), day(14, 0)), weeks[1])
+	assert.Equal(t, time.Monday, weeks[6].Start.Weekday())
+	assert.Equal(t, []Range{
+		mustRange(t, day(2, 9), time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)),
+		mustRange(t, time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)),
+	}, months)
+	assert.Nil(t, Range{}.SplitByDay(nil))
+}
+
+func TestRangeIterate(t *testing.T) {
+	// arrange
+	start := time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+	r := mustRange(t, start, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC))
+	var monthly []string
+	var hourly int
+
+	// act
+	r.Iterate(Period{Months: 1}, func(t time.Time) bool {
+		monthly = append(monthly, t.Format("2006-01-02"))
+		return true
+	})
+	r.Iterate(Period{Time: time.Hour}, func(t time.Time) bool {
+		hourly++
+		return hourly < 5
+	})
+	r.Iterate(Period{}, func(t time.Time) bool {
+		hourly++
+		return true
+	})
+
+	// assert
+	assert.Equal(t, []string{"2021-01-31", "2021-03-03", "2021-03-31"}, monthly)
+	assert.Equal(t, 6, hourly)
+}
+
+func TestRangeJSONSuccessful(t *testing.T) {
+	// arrange
+	r := mustRange(t, day(1, 14), day(3, 10))
+	var decoded Range
+	var lenient Range
+
+	// act
+	data, err := json.Marshal(r)
+	decodeErr := json.Unmarshal(data, &decoded)
+	lenientErr := json.Unmarshal([]byte(`{"start":"2021-06-01","end":"1622894400"}`), &lenient)
+	invalidErr := json.Unmarshal([]byte(`{"start":"2021-06-03","end":"2021-06-01"}`), &decoded)
+	var wrapper struct {
+		Period Range `json:"period"`
+	}
+	nullErr := json.Unmarshal([]byte(`{"period":null}`), &wrapper)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, `{"start":"2021-06-01T14:00:00Z","end":"2021-06-03T10:00:00Z"}`, string(data))
+	assert.Nil(t, decodeErr)
+	assert.Equal(t, r, decoded)
+	assert.Nil(t, lenientErr)
+	assert.Equal(t, mustRange(t, day(1, 0), day(5, 12)), lenient)
+	assert.Equal(t, ErrInvalidRange, invalidErr)
+	assert.Nil(t, nullErr)
+	assert.Equal(t, Range{}, wrapper.Period)
+}
+
+func TestRangeSQLSuccessful(t *testing.T) {
+	// arrange
+	r := mustRange(t, day(1, 14), day(3, 10))
+	var scanned, postgres, null Range
+
+	// act
+	value, err := r.Value()
+	scanErr := scanned.Scan([]byte(value.(string)))
+	postgresErr := postgres.Scan(`["2021-06-01 16:00:00+02","2021-06-03 10:00:00+00")`)
+	nullErr := null.Scan(nil)
+	var closed Range
+	closedErr := closed.Scan("[2021-06-01T00:00:00Z,2021-06-02T00:00:00Z]")
+	malformedErr := closed.Scan("{2021-06-01T00:00:00Z,2021-06-02T00:00:00Z}")
+	typeErr := scanned.Scan(42)
+	var exclusive, empty Range
+	exclusiveErr := exclusive.Scan(`("2021-06-01 13:59:59.999999+00","2021-06-03 09:59:59.999999+00"]`)
+	emptyValue, emptyValueErr := Range{}.Value()
+	emptyErr := empty.Scan(emptyValue)
+	unboundedErr := scanned.Scan(`["2021-06-01 00:00:00+00",)`)
+	infiniteErr := scanned.Scan(`["2021-06-01 00:00:00+00",infinity)`)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "[2021-06-01T14:00:00Z,2021-06-03T10:00:00Z)", value)
+	assert.Nil(t, scanErr)
+	assert.Equal(t, r, scanned)
+	assert.Nil(t, postgresErr)
+	assert.Equal(t, r, postgres)
+	assert.Nil(t, nullErr)
+	assert.Equal(t, Range{}, null)
+	assert.Nil(t, closedErr)
+	assert.Equal(t, mustRange(t, day(1, 0), day(2, 0).Add(time.Microsecond)), closed)
+	assert.Equal(t, ErrInvalidRangeValue, malformedErr)
+	assert.NotNil(t, typeErr)
+	assert.Nil(t, exclusiveErr)
+	assert.True(t, r.Start.Equal(exclusive.Start))
+	assert.True(t, r.End.Equal(exclusive.End))
+	assert.Nil(t, emptyValueErr)
+	assert.Equal(t, "empty", emptyValue)
+	assert.Nil(t, emptyErr)
+	assert.Equal(t, Range{}, empty)
+	assert.Equal(t, ErrUnboundedRange, unboundedErr)
+	assert.Equal(t, ErrUnboundedRange, infiniteErr)
+	assert.Equal(t, r, r.Columns().Range())
+}
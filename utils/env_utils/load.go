@@ -0,0 +1,340 @@
+package env_utils
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+)
+
+var (
+	// ErrInvalidTarget returned when the config is not a pointer to a struct
+	ErrInvalidTarget = errors.New("env: config must be a non nil pointer to a struct")
+	// ErrRequired reported for required variables which are not set
+	ErrRequired = errors.New("required variable is not set")
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+//FieldError missing or malformed variable
+type FieldError struct {
+	// Key name of the environment variable, prefix included
+	Key string
+	// Field path of the struct field, such as Database.Port
+	Field string
+	Err   error
+}
+
+//Error formats the variable and the reason
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Field, e.Err)
+}
+
+//LoadError every missing or malformed variable found by Load
+type LoadError struct {
+	Errors []FieldError
+}
+
+//Error lists every variable in error
+func (e *LoadError) Error() string {
+	reasons := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		reasons = append(reasons, err.Error())
+	}
+	return fmt.Sprintf("env: %d invalid variables: %s", len(e.Errors), strings.Join(reasons, "; "))
+}
+
+//Load fills the config struct from environment variables, see LoadWithPrefix
+func Load(config interface{}) error {
+	return LoadWithPrefix("", config)
+}
+
+//LoadWithPrefix fills the config struct from environment variables named prefix followed by the env tag of each
+//field. Tags:
+//
+//	env:"PORT"                  variable of the field, env:"PORT,required" fails when it is not set
+//	envDefault:"8080"           value used when the variable is not set
+//	envSeparator:";"            separator of slice and map items, a comma by default
+//	envLayout:"2006-01-02"      layout of time.Time fields, any layout of date_utils.ParseDateTime by default
+//	envPrefix:"DB_"             prefix added to the variables of a nested struct field
+//
+//Nil pointers to nested structs are only allocated when at least one of their variables is set, their defaults then
+//apply, so that optional sections stay nil and their required variables are not reported.
+//Supported types are strings, base 10 integers, floats, bools, time.Duration, time.Time, url.URL,
+//encoding.TextUnmarshaler implementations, pointers to those, slices of those and maps with string keys written as
+//key:value items.
+//Variables set to an empty string count as not set. Every missing or malformed variable is reported in a single
+//*LoadError.
+func LoadWithPrefix(prefix string, config interface{}) error {
+	value := reflect.ValueOf(config)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	loadErr := &LoadError{}
+	loadStruct(value.Elem(), prefix, "", loadErr)
+	if len(loadErr.Errors) > 0 {
+		return loadErr
+	}
+	return nil
+}
+
+//loadStruct fills the fields of a struct value, collecting the errors. Returns whether any variable was set,
+//defaults aside.
+func loadStruct(value reflect.Value, prefix string, path string, loadErr *LoadError) bool {
+	set := false
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		if tag == "-" {
+			continue
+		}
+
+		if !hasTag && isNestedStruct(field.Type) {
+			nestedPrefix := prefix + field.Tag.Get("envPrefix")
+			if field.Type.Kind() != reflect.Ptr {
+				set = loadStruct(fieldValue, nestedPrefix, fieldPath, loadErr) || set
+			} else if !fieldValue.IsNil() {
+				set = loadStruct(fieldValue.Elem(), nestedPrefix, fieldPath, loadErr) || set
+			} else {
+				// loaded aside, and kept with its errors only when one of its variables is set
+				nested := reflect.New(field.Type.Elem())
+				nestedErr := &LoadError{}
+				if loadStruct(nested.Elem(), nestedPrefix, fieldPath, nestedErr) {
+					fieldValue.Set(nested)
+					loadErr.Errors = append(loadErr.Errors, nestedErr.Errors...)
+					set = true
+				}
+			}
+			continue
+		}
+		if !hasTag {
+			continue
+		}
+
+		name, options := tag, ""
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		key := prefix + name
+
+		raw, ok := os.LookupEnv(key)
+		if ok && raw != "" {
+			set = true
+		} else {
+			raw, ok = field.Tag.Lookup("envDefault")
+		}
+		if !ok {
+			if hasOption(options, "required") {
+				loadErr.Errors = append(loadErr.Errors, FieldError{Key: key, Field: fieldPath, Err: ErrRequired})
+			}
+			continue
+		}
+
+		if err := setValue(fieldValue, raw, field.Tag); err != nil {
+			loadErr.Errors = append(loadErr.Errors, FieldError{Key: key, Field: fieldPath, Err: err})
+		}
+	}
+	return set
+}
+
+//isNestedStruct checks if the type is a struct, or a pointer to one, whose fields are loaded individually
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && t != urlType && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+//hasOption checks if the comma separated options contain option
+func hasOption(options string, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+	return false
+}
+
+//setValue parses raw into the field according to its type
+func setValue(value reflect.Value, raw string, tag reflect.StructTag) error {
+	separator := ","
+	if s, ok := tag.Lookup("envSeparator"); ok && s != "" {
+		separator = s
+	}
+
+	// slices such as net.IP parse themselves
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return setScalar(value, raw, tag)
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			value.SetBytes([]byte(raw))
+			return nil
+		}
+		items := splitItems(raw, separator)
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setScalar(slice.Index(i), item, tag); err != nil {
+				return fmt.Errorf("item %d: %v", i, err)
+			}
+		}
+		value.Set(slice)
+		return nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", value.Type().Key())
+		}
+		m := reflect.MakeMap(value.Type())
+		for _, item := range splitItems(raw, separator) {
+			k, v, ok := strings.Cut(item, ":")
+			if !ok {
+				return fmt.Errorf("map item %q is not key:value", item)
+			}
+			mapValue := reflect.New(value.Type().Elem()).Elem()
+			if err := setScalar(mapValue, strings.TrimSpace(v), tag); err != nil {
+				return fmt.Errorf("key %s: %v", k, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)).Convert(value.Type().Key()), mapValue)
+		}
+		value.Set(m)
+		return nil
+	}
+	return setScalar(value, raw, tag)
+}
+
+//splitItems splits a list, trimming the items and ignoring empty ones
+func splitItems(raw string, separator string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+//setScalar parses raw into a single value, allocating pointers
+func setScalar(value reflect.Value, raw string, tag reflect.StructTag) error {
+	if value.Kind() == reflect.Ptr {
+		target := reflect.New(value.Type().Elem())
+		if err := setScalar(target.Elem(), raw, tag); err != nil {
+			return err
+		}
+		value.Set(target)
+		return nil
+	}
+
+	switch value.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			// ISO 8601 durations such as PT30S are accepted too
+			if d, err = date_utils.ParseISODuration(raw); err != nil {
+				return fmt.Errorf("invalid duration %q", raw)
+			}
+		}
+		value.SetInt(int64(d))
+		return nil
+	case timeType:
+		var t time.Time
+		var err error
+		if layout := tag.Get("envLayout"); layout != "" {
+			t, err = time.Parse(layout, raw)
+		} else {
+			t, err = date_utils.ParseDateTime(raw, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid time %q", raw)
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+	case urlType:
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" && u.Opaque == "" && u.Path == "" {
+			return fmt.Errorf("invalid url %q", raw)
+		}
+		value.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid value %q: %v", raw, err)
+		}
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Kind(), raw)
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Kind(), raw)
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Kind(), raw)
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+//parseBool accepts the strconv.ParseBool values as well as yes, no, on and off
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid bool %q", raw)
+	}
+	return b, nil
+}
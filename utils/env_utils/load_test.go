@@ -0,0 +1,146 @@
+package env_utils
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type databaseConfig struct {
+	Host     string        `env:"HOST,required"`
+	Port     int           `env:"PORT" envDefault:"3306"`
+	Timeout  time.Duration `env:"TIMEOUT" envDefault:"5s"`
+	Replicas []string      `env:"REPLICAS"`
+}
+
+type proxyConfig struct {
+	URL     *url.URL `env:"URL,required"`
+	Retries int      `env:"RETRIES"`
+}
+
+type serviceConfig struct {
+	Name       string             `env:"NAME" envDefault:"api"`
+	Debug      bool               `env:"DEBUG"`
+	Ratio      float64            `env:"RATIO"`
+	MaxUploads uint16             `env:"MAX_UPLOADS"`
+	Launch     time.Time          `env:"LAUNCH" envLayout:"2006-01-02"`
+	Started    time.Time          `env:"STARTED"`
+	Callback   *url.URL           `env:"CALLBACK_URL"`
+	Ports      []int              `env:"PORTS" envSeparator:";"`
+	Limits     map[string]int     `env:"LIMITS"`
+	IP         net.IP             `env:"IP"`
+	Retention  *time.Duration     `env:"RETENTION"`
+	Unset      *int               `env:"UNSET"`
+	Ignored    string             `env:"-"`
+	Database   databaseConfig     `envPrefix:"DB_"`
+	Cache      *databaseConfig    `envPrefix:"CACHE_"`
+	Labels     map[string]float64 `env:"LABELS" envDefault:"a:1.5, b:2"`
+	Proxy      *proxyConfig       `envPrefix:"PROXY_"`
+	internal   string
+}
+
+func TestLoadWithPrefixSuccessful(t *testing.T) {
+	// arrange
+	t.Setenv("APP_DEBUG", "yes")
+	t.Setenv("APP_RATIO", "0.75")
+	t.Setenv("APP_MAX_UPLOADS", "010")
+	t.Setenv("APP_LAUNCH", "2021-06-01")
+	t.Setenv("APP_STARTED", "1622550615")
+	t.Setenv("APP_CALLBACK_URL", "https://example.com/hooks?x=1")
+	t.Setenv("APP_PORTS", "80; 443")
+	t.Setenv("APP_LIMITS", "free:10,pro:100")
+	t.Setenv("APP_IP", "10.0.0.1")
+	t.Setenv("APP_RETENTION", "P1D")
+	t.Setenv("APP_IGNORED", "value")
+	t.Setenv("APP_DB_HOST", "db.internal")
+	t.Setenv("APP_DB_PORT", "")
+	t.Setenv("APP_DB_REPLICAS", "r1, r2,")
+	t.Setenv("APP_CACHE_HOST", "cache.internal")
+	t.Setenv("APP_CACHE_TIMEOUT", "250ms")
+	config := serviceConfig{}
+
+	// act
+	err := LoadWithPrefix("APP_", &config)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "api", config.Name)
+	assert.True(t, config.Debug)
+	assert.Equal(t, 0.75, config.Ratio)
+	assert.Equal(t, uint16(10), config.MaxUploads)
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), config.Launch)
+	assert.Equal(t, int64(1622550615), config.Started.Unix())
+	assert.Equal(t, "example.com", config.Callback.Host)
+	assert.Equal(t, []int{80, 443}, config.Ports)
+	assert.Equal(t, map[string]int{"free": 10, "pro": 100}, config.Limits)
+	assert.Equal(t, "10.0.0.1", config.IP.String())
+	assert.Equal(t, 24*time.Hour, *config.Retention)
+	assert.Nil(t, config.Unset)
+	assert.Equal(t, "", config.Ignored)
+	assert.Equal(t, databaseConfig{Host: "db.internal", Port: 3306, Timeout: 5 * time.Second, Replicas: []string{"r1", "r2"}}, config.Database)
+	assert.Equal(t, &databaseConfig{Host: "cache.internal", Port: 3306, Timeout: 250 * time.Millisecond}, config.Cache)
+	assert.Equal(t, map[string]float64{"a": 1.5, "b": 2}, config.Labels)
+	assert.Nil(t, config.Proxy)
+}
+
+func TestLoadOptionalNestedStruct(t *testing.T) {
+	// arrange
+	t.Setenv("OPT_DB_HOST", "db.internal")
+	t.Setenv("OPT_PROXY_RETRIES", "08")
+	config := serviceConfig{}
+
+	// act
+	err := LoadWithPrefix("OPT_", &config)
+
+	// assert
+	var loadErr *LoadError
+	assert.True(t, errors.As(err, &loadErr))
+	assert.Len(t, loadErr.Errors, 1)
+	assert.Equal(t, "OPT_PROXY_URL", loadErr.Errors[0].Key)
+	assert.Equal(t, ErrRequired, loadErr.Errors[0].Err)
+	assert.Equal(t, 8, config.Proxy.Retries)
+	assert.Nil(t, config.Cache)
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	// arrange
+	t.Setenv("SVC_DEBUG", "tru")
+	t.Setenv("SVC_MAX_UPLOADS", "70000")
+	t.Setenv("SVC_CALLBACK_URL", "not a url")
+	t.Setenv("SVC_PORTS", "80;http")
+	t.Setenv("SVC_LIMITS", "free=10")
+	t.Setenv("SVC_CACHE_HOST", "cache.internal")
+	config := serviceConfig{}
+
+	// act
+	err := LoadWithPrefix("SVC_", &config)
+
+	// assert
+	var loadErr *LoadError
+	assert.True(t, errors.As(err, &loadErr))
+	var keys []string
+	for _, fieldErr := range loadErr.Errors {
+		keys = append(keys, fieldErr.Key)
+	}
+	assert.Equal(t, []string{"SVC_DEBUG", "SVC_MAX_UPLOADS", "SVC_CALLBACK_URL", "SVC_PORTS", "SVC_LIMITS", "SVC_DB_HOST"}, keys)
+	assert.Equal(t, ErrRequired, loadErr.Errors[5].Err)
+	assert.Equal(t, "Database.Host", loadErr.Errors[5].Field)
+	assert.Contains(t, err.Error(), "env: 6 invalid variables: ")
+	assert.Contains(t, err.Error(), `SVC_DEBUG (Debug): invalid bool "tru"`)
+	assert.Contains(t, err.Error(), "SVC_DB_HOST (Database.Host): required variable is not set")
+}
+
+func TestLoadInvalidTarget(t *testing.T) {
+	// arrange
+	config := serviceConfig{}
+	var nilConfig *serviceConfig
+
+	// act & assert
+	assert.Equal(t, ErrInvalidTarget, Load(config))
+	assert.Equal(t, ErrInvalidTarget, Load(nilConfig))
+	assert.Equal(t, ErrInvalidTarget, Load(new(string)))
+}
@@ -5,11 +5,13 @@ import (
 )
 
 const (
-	InternalCustomError = "internal_custom_error"
-	InternalServerError = "internal_server_error"
-	BadRequestError     = "bad_request_error"
-	UnAuthorizedError   = "unauthorized_error"
-	ForbiddenError      = "forbidden_error"
+	InternalCustomError       = "internal_custom_error"
+	InternalServerError       = "internal_server_error"
+	BadRequestError           = "bad_request_error"
+	UnAuthorizedError         = "unauthorized_error"
+	ForbiddenError            = "forbidden_error"
+	PayloadTooLargeError      = "payload_too_large_error"
+	UnsupportedMediaTypeError = "unsupported_media_type_error"
 )
 
 type ApiError struct {
@@ -63,3 +65,21 @@ func NewForbiddenError(message string) *ApiError {
 		ErrorConst:         ForbiddenError,
 	}
 }
+
+func NewPayloadTooLargeError(message string) *ApiError {
+	return &ApiError{
+		HttpStatusCode:     http.StatusRequestEntityTooLarge,
+		InternalStatusCode: http.StatusRequestEntityTooLarge,
+		ErrorMessage:       message,
+		ErrorConst:         PayloadTooLargeError,
+	}
+}
+
+func NewUnsupportedMediaTypeError(message string) *ApiError {
+	return &ApiError{
+		HttpStatusCode:     http.StatusUnsupportedMediaType,
+		InternalStatusCode: http.StatusUnsupportedMediaType,
+		ErrorMessage:       message,
+		ErrorConst:         UnsupportedMediaTypeError,
+	}
+}
@@ -81,3 +81,35 @@ func TestForbiddenErrorSuccessful(t *testing.T) {
 	assert.EqualValues(t, http.StatusForbidden, apiError.HttpStatusCode)
 	assert.EqualValues(t, http.StatusForbidden, apiError.InternalStatusCode)
 }
+
+func TestPayloadTooLargeErrorSuccessful(t *testing.T) {
+
+	//arrange
+	message := "Payload Too Large Error"
+
+	//act
+	apiError := NewPayloadTooLargeError(message)
+
+	//assert
+	assert.NotNil(t, apiError)
+	assert.EqualValues(t, message, apiError.ErrorMessage)
+	assert.EqualValues(t, http.StatusRequestEntityTooLarge, apiError.HttpStatusCode)
+	assert.EqualValues(t, http.StatusRequestEntityTooLarge, apiError.InternalStatusCode)
+	assert.EqualValues(t, PayloadTooLargeError, apiError.ErrorConst)
+}
+
+func TestUnsupportedMediaTypeErrorSuccessful(t *testing.T) {
+
+	//arrange
+	message := "Unsupported Media Type Error"
+
+	//act
+	apiError := NewUnsupportedMediaTypeError(message)
+
+	//assert
+	assert.NotNil(t, apiError)
+	assert.EqualValues(t, message, apiError.ErrorMessage)
+	assert.EqualValues(t, http.StatusUnsupportedMediaType, apiError.HttpStatusCode)
+	assert.EqualValues(t, http.StatusUnsupportedMediaType, apiError.InternalStatusCode)
+	assert.EqualValues(t, UnsupportedMediaTypeError, apiError.ErrorConst)
+}
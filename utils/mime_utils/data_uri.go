@@ -0,0 +1,188 @@
+package mime_utils
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lelinu/api_utils/utils/base64_utils"
+)
+
+const (
+	dataURIScheme           = "data:"
+	defaultDataURIMediaType = "text/plain"
+	octetStream             = "application/octet-stream"
+)
+
+var (
+	ErrInvalidDataURI    = errors.New("mime: invalid data uri")
+	ErrDataURITooLarge   = errors.New("mime: data uri content exceeds the size limit")
+	ErrUnknownMediaType  = errors.New("mime: unknown media type")
+	ErrMediaTypeMismatch = errors.New("mime: media type does not match the content")
+	ErrExtensionMismatch = errors.New("mime: media type does not match the file extension")
+)
+
+//DataURI parsed RFC 2397 data uri
+type DataURI struct {
+	MediaType string
+	Params    map[string]string
+	Data      []byte
+}
+
+//ParseDataURI parses a data:[<media type>][;base64],<data> uri. Decoded content larger than maxSize bytes is
+//rejected, before decoding when the encoded length already exceeds it. maxSize 0 means no limit.
+func ParseDataURI(uri string, maxSize int) (*DataURI, error) {
+	if len(uri) < len(dataURIScheme) || !strings.EqualFold(uri[:len(dataURIScheme)], dataURIScheme) {
+		return nil, ErrInvalidDataURI
+	}
+
+	comma := strings.IndexByte(uri, ',')
+	if comma < 0 {
+		return nil, ErrInvalidDataURI
+	}
+	header, payload := uri[len(dataURIScheme):comma], uri[comma+1:]
+
+	isBase64 := false
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		isBase64 = true
+		header = header[:len(header)-len(";base64")]
+	}
+
+	dataURI := &DataURI{MediaType: defaultDataURIMediaType, Params: map[string]string{}}
+	if header == "" {
+		dataURI.Params["charset"] = "US-ASCII"
+	} else {
+		if strings.HasPrefix(header, ";") {
+			header = defaultDataURIMediaType + header
+		}
+		mediaType, params, err := mime.ParseMediaType(header)
+		if err != nil {
+			return nil, ErrInvalidDataURI
+		}
+		dataURI.MediaType = mediaType
+		dataURI.Params = params
+	}
+
+	var err error
+	if isBase64 {
+		if maxSize > 0 && len(payload) > (maxSize+2)/3*4+4 {
+			return nil, ErrDataURITooLarge
+		}
+		dataURI.Data, err = base64_utils.DecodeAuto(payload)
+	} else {
+		if maxSize > 0 && len(payload) > maxSize*3 {
+			return nil, ErrDataURITooLarge
+		}
+		var unescaped string
+		unescaped, err = url.PathUnescape(payload)
+		dataURI.Data = []byte(unescaped)
+	}
+	if err != nil {
+		return nil, ErrInvalidDataURI
+	}
+	if maxSize > 0 && len(dataURI.Data) > maxSize {
+		return nil, ErrDataURITooLarge
+	}
+
+	return dataURI, nil
+}
+
+//BuildDataURI returns the base64 data uri of the content
+func BuildDataURI(mediaType string, data []byte) string {
+	return BuildDataURIWithParams(mediaType, nil, data)
+}
+
+//BuildDataURIWithParams returns the base64 data uri of the content, with media type parameters such as charset
+func BuildDataURIWithParams(mediaType string, params map[string]string, data []byte) string {
+	header := mime.FormatMediaType(mediaType, params)
+	if header == "" {
+		header = octetStream
+	}
+	return fmt.Sprintf("%s%s;base64,%s", dataURIScheme, header, base64_utils.EncodeFromBytes(data))
+}
+
+//Verify rejects mislabelled payloads: the media type must be known, match the extension of fileName when
+//it is not empty, and not contradict the type sniffed from the content
+func (d *DataURI) Verify(fileName string) error {
+	if !IsKnownMimeType(d.MediaType) {
+		return ErrUnknownMediaType
+	}
+
+	if fileName != "" && GetMimeType(fileName) != d.MediaType {
+		return ErrExtensionMismatch
+	}
+
+	if !contentMatchesMediaType(d.Data, d.MediaType) {
+		return ErrMediaTypeMismatch
+	}
+	return nil
+}
+
+//IsKnownMimeType checks if the media type is registered in DefaultRegistry
+func IsKnownMimeType(mediaType string) bool {
+	return DefaultRegistry.IsKnown(mediaType)
+}
+
+//contentMatchesMediaType checks the declared media type against the one detected from the magic bytes,
+//falling back to the standard library sniffing for text formats.
+//Unrecognised binary content is only accepted for media types without magic bytes, such as image/x-raw.
+func contentMatchesMediaType(data []byte, mediaType string) bool {
+	if detection, ok := Detect(data); ok {
+		// weak detections, such as a csv starting with MZ, fall back to sniffing when they disagree
+		matches := detection.MatchesMediaType(mediaType)
+		if matches || detection.Confidence > ConfidenceLow {
+			return matches
+		}
+	}
+
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || sniffed == octetStream {
+		return !mediaTypeHasSignature(mediaType)
+	}
+
+	switch sniffed {
+	case "text/plain":
+		return isTextMediaType(mediaType)
+	case "text/xml":
+		return mediaType == "text/xml" || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+	}
+	return sniffed == mediaType
+}
+
+//mediaTypeHasSignature checks if content of the media type starts with known magic bytes, either as the
+//detected type of a signature or as the registered type of one of its extensions, such as docx for zip
+func mediaTypeHasSignature(mediaType string) bool {
+	if mediaType == octetStream {
+		return false
+	}
+	for i := range signatures {
+		s := &signatures[i]
+		if s.optional {
+			continue
+		}
+		if s.mediaType == mediaType {
+			return true
+		}
+		for _, extension := range s.extensions {
+			if registered, ok := DefaultRegistry.TypeByExtension(extension); ok && registered == mediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//isTextMediaType checks if content of the media type is expected to be readable text
+func isTextMediaType(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-javascript", "image/svg+xml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package mime_utils
+
+import (
+	"testing"
+
+	"github.com/lelinu/api_utils/utils/base64_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestParseDataURIBase64Successful(t *testing.T) {
+	// arrange
+	uri := "data:image/png;base64," + base64_utils.EncodeFromBytes(pngHeader)
+
+	// act
+	dataURI, err := ParseDataURI(uri, 1024)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", dataURI.MediaType)
+	assert.Equal(t, pngHeader, dataURI.Data)
+	assert.Nil(t, dataURI.Verify("avatar.png"))
+}
+
+func TestParseDataURIPercentEncodedWithParams(t *testing.T) {
+	// act
+	dataURI, err := ParseDataURI("data:text/plain;charset=UTF-8,hello%20world", 0)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "text/plain", dataURI.MediaType)
+	assert.Equal(t, "UTF-8", dataURI.Params["charset"])
+	assert.Equal(t, "hello world", string(dataURI.Data))
+}
+
+func TestParseDataURIDefaultMediaType(t *testing.T) {
+	// act
+	dataURI, err := ParseDataURI("data:,hello", 0)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "text/plain", dataURI.MediaType)
+	assert.Equal(t, "US-ASCII", dataURI.Params["charset"])
+}
+
+func TestParseDataURIInvalid(t *testing.T) {
+	// act
+	_, schemeErr := ParseDataURI("http://example.com/a.png", 0)
+	_, commaErr := ParseDataURI("data:image/png;base64", 0)
+	_, base64Err := ParseDataURI("data:image/png;base64,!!!", 0)
+
+	// assert
+	assert.Equal(t, ErrInvalidDataURI, schemeErr)
+	assert.Equal(t, ErrInvalidDataURI, commaErr)
+	assert.Equal(t, ErrInvalidDataURI, base64Err)
+}
+
+func TestParseDataURITooLarge(t *testing.T) {
+	// arrange
+	data := make([]byte, 100)
+
+	// act
+	_, exactErr := ParseDataURI(BuildDataURI("application/octet-stream", data), 100)
+	_, overErr := ParseDataURI(BuildDataURI("application/octet-stream", data), 99)
+	_, earlyErr := ParseDataURI(BuildDataURI("application/octet-stream", make([]byte, 10000)), 100)
+
+	// assert
+	assert.Nil(t, exactErr)
+	assert.Equal(t, ErrDataURITooLarge, overErr)
+	assert.Equal(t, ErrDataURITooLarge, earlyErr)
+}
+
+func TestBuildDataURIRoundTrip(t *testing.T) {
+	// act
+	uri := BuildDataURIWithParams("text/csv", map[string]string{"charset": "utf-8"}, []byte("a,b\n1,2"))
+	dataURI, err := ParseDataURI(uri, 0)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "data:text/csv; charset=utf-8;base64,YSxiCjEsMg==", uri)
+	assert.Equal(t, "a,b\n1,2", string(dataURI.Data))
+	assert.Nil(t, dataURI.Verify("export.csv"))
+}
+
+func TestDataURIVerifyRejectsMislabelledContent(t *testing.T) {
+	// arrange
+	executable := &DataURI{MediaType: "image/png", Data: []byte("MZ\x90\x00\x03\x00\x00\x00")}
+	html := &DataURI{MediaType: "image/png", Data: []byte("<html><script>alert(1)</script></html>")}
+	png := &DataURI{MediaType: "image/jpeg", Data: pngHeader}
+	random := &DataURI{MediaType: "image/png", Data: []byte{0x13, 0x37, 0x00, 0xfe, 0x01, 0x02, 0x03, 0x04}}
+	pdf := &DataURI{MediaType: "application/pdf", Data: []byte{0x00, 0x01, 0x02, 0x03}}
+	document := &DataURI{MediaType: "application/zip", Data: []byte{0x00, 0x01}}
+
+	// act & assert
+	assert.Equal(t, ErrMediaTypeMismatch, executable.Verify(""))
+	assert.Equal(t, ErrMediaTypeMismatch, html.Verify(""))
+	assert.Equal(t, ErrMediaTypeMismatch, png.Verify(""))
+	assert.Equal(t, ErrMediaTypeMismatch, random.Verify(""))
+	assert.Equal(t, ErrMediaTypeMismatch, pdf.Verify(""))
+	assert.Equal(t, ErrMediaTypeMismatch, document.Verify(""))
+	assert.Equal(t, ErrExtensionMismatch, png.Verify("photo.png"))
+	assert.Equal(t, ErrUnknownMediaType, (&DataURI{MediaType: "image/unknown"}).Verify(""))
+}
+
+func TestDataURIVerifyAcceptsUnrecognisedContentWithoutSignature(t *testing.T) {
+	// arrange
+	binary := &DataURI{MediaType: "application/octet-stream", Data: []byte{0x13, 0x37, 0x00, 0xfe}}
+	raw := &DataURI{MediaType: "image/x-raw", Data: []byte{0x13, 0x37, 0x00, 0xfe}}
+	csv := &DataURI{MediaType: "text/csv", Data: []byte("MZ,Mozambique\nZA,South Africa\n")}
+
+	// act & assert
+	assert.Nil(t, binary.Verify(""))
+	assert.Nil(t, raw.Verify("photo.raw"))
+	assert.Nil(t, csv.Verify("countries.csv"))
+}
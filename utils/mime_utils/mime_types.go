@@ -1,6 +1,8 @@
 package mime_utils
 
 var (
+	// MimeTypes built in extension to mime type mappings, only used to seed DefaultRegistry at start up.
+	// Changes made to it afterwards are ignored, use DefaultRegistry.Register which is also safe for concurrent use.
 	MimeTypes map[string]string = map[string]string{
 		"txt":        "text/plain",
 		"3fr":        "image/x-hasselblad-3fr",
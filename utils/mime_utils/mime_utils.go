@@ -1,19 +1,6 @@
 package mime_utils
 
-import (
-	"path/filepath"
-	"strings"
-)
-
+//GetMimeType returns the mime type of the file extension from DefaultRegistry, application/octet-stream when unknown
 func GetMimeType(p string) string {
-	ext := filepath.Ext(p)
-	if ext != "" {
-		ext = ext[1:]
-	}
-	ext = strings.ToLower(ext)
-	mType := MimeTypes[ext]
-	if mType == "" {
-		return "application/octet-stream"
-	}
-	return mType
+	return DefaultRegistry.TypeByFileName(p)
 }
@@ -61,3 +61,19 @@ func TestGetMimeTypeValidPathJpeg(t *testing.T) {
 	assert.EqualValues(t, expected, value)
 }
 
+func TestGetMimeTypeUsesDefaultRegistry(t *testing.T) {
+	//arrange
+	defaultRegistry := DefaultRegistry
+	DefaultRegistry = NewDefaultRegistry()
+	defer func() { DefaultRegistry = defaultRegistry }()
+	assert.Nil(t, DefaultRegistry.Register("image/x-custom-jpeg", "jpg"))
+	assert.Nil(t, DefaultRegistry.Register("application/x-registered", "registered"))
+
+	//act
+	overridden := GetMimeType("/hello/test.jpg")
+	registered := GetMimeType("/hello/test.REGISTERED")
+
+	//assert
+	assert.EqualValues(t, "image/x-custom-jpeg", overridden)
+	assert.EqualValues(t, "application/x-registered", registered)
+}
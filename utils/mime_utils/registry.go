@@ -0,0 +1,278 @@
+package mime_utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Category broad family of a mime type
+type Category string
+
+const (
+	CategoryImage    Category = "image"
+	CategoryVideo    Category = "video"
+	CategoryAudio    Category = "audio"
+	CategoryDocument Category = "document"
+	CategoryArchive  Category = "archive"
+	CategoryText     Category = "text"
+	CategoryOther    Category = "other"
+)
+
+var (
+	// DefaultRegistry registry seeded with MimeTypes, used by GetMimeType
+	DefaultRegistry = NewDefaultRegistry()
+
+	documentMimeTypes = map[string]bool{
+		"application/pdf":               true,
+		"application/rtf":               true,
+		"application/msword":            true,
+		"application/word":              true,
+		"application/excel":             true,
+		"application/powerpoint":        true,
+		"application/postscript":        true,
+		"application/vnd.ms-excel":      true,
+		"application/vnd.ms-powerpoint": true,
+		"text/csv":                      true,
+		"text/markdown":                 true,
+	}
+	archiveMimeTypes = map[string]bool{
+		"application/zip":                         true,
+		"application/gzip":                        true,
+		"application/x-gzip":                      true,
+		"application/x-bz2":                       true,
+		"application/x-bzip2":                     true,
+		"application/x-xz":                        true,
+		"application/x-tar":                       true,
+		"application/x-7z-compressed":             true,
+		"application/x-rar-compressed":            true,
+		"application/vnd.rar":                     true,
+		"application/vnd.ms-cab-compressed":       true,
+		"application/java-archive":                true,
+		"application/vnd.android.package-archive": true,
+	}
+)
+
+//Registry two way mapping between extensions and mime types, safe for concurrent use
+type Registry struct {
+	mu          sync.RWMutex
+	byExtension map[string]string
+	byMimeType  map[string][]string
+	categories  map[string]Category
+}
+
+//NewRegistry constructor for an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byExtension: map[string]string{},
+		byMimeType:  map[string][]string{},
+		categories:  map[string]Category{},
+	}
+}
+
+//NewDefaultRegistry constructor for a registry holding MimeTypes
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	extensions := make([]string, 0, len(MimeTypes))
+	for extension := range MimeTypes {
+		extensions = append(extensions, extension)
+	}
+	// registered in order so that the reverse lookup is deterministic
+	sort.Strings(extensions)
+	for _, extension := range extensions {
+		_ = r.Register(MimeTypes[extension], extension)
+	}
+	return r
+}
+
+//Register maps the extensions, with or without their dot, to the mime type. An extension already mapped to another
+//type is moved to this one. The first extension registered for a type is the one returned by ExtensionByType.
+func (r *Registry) Register(mimeType string, extensions ...string) error {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return fmt.Errorf("mime: invalid mime type %q: %v", mimeType, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, extension := range extensions {
+		extension = normalizeExtension(extension)
+		if extension == "" {
+			return fmt.Errorf("mime: empty extension for %s", mediaType)
+		}
+
+		if previous, ok := r.byExtension[extension]; ok {
+			if previous == mediaType {
+				continue
+			}
+			r.byMimeType[previous] = removeString(r.byMimeType[previous], extension)
+		}
+		r.byExtension[extension] = mediaType
+		r.byMimeType[mediaType] = append(r.byMimeType[mediaType], extension)
+	}
+	return nil
+}
+
+//TypeByExtension returns the mime type of the extension, with or without its dot
+func (r *Registry) TypeByExtension(extension string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mimeType, ok := r.byExtension[normalizeExtension(extension)]
+	return mimeType, ok
+}
+
+//TypeByFileName returns the mime type of the file extension, application/octet-stream when unknown
+func (r *Registry) TypeByFileName(fileName string) string {
+	if mimeType, ok := r.TypeByExtension(filepath.Ext(fileName)); ok {
+		return mimeType
+	}
+	return octetStream
+}
+
+//ExtensionsByType returns the extensions of the mime type, parameters such as charset are ignored
+func (r *Registry) ExtensionsByType(mimeType string) []string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.byMimeType[mediaType]...)
+}
+
+//ExtensionByType returns the preferred extension of the mime type, without its dot
+func (r *Registry) ExtensionByType(mimeType string) (string, bool) {
+	extensions := r.ExtensionsByType(mimeType)
+	if len(extensions) == 0 {
+		return "", false
+	}
+	return extensions[0], true
+}
+
+//IsKnown checks if an extension is registered for the mime type
+func (r *Registry) IsKnown(mimeType string) bool {
+	return len(r.ExtensionsByType(mimeType)) > 0
+}
+
+//Load registers the mappings of a mime.types file, made of lines such as "image/jpeg jpeg jpg" and # comments
+func (r *Registry) Load(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			continue
+		}
+		if err := r.Register(fields[0], fields[1:]...); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+//LoadFile registers the mappings of a mime.types file such as /etc/mime.types
+func (r *Registry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := r.Load(file); err != nil {
+		return fmt.Errorf("mime: %s: %v", path, err)
+	}
+	return nil
+}
+
+//SetCategory overrides the category of a mime type
+func (r *Registry) SetCategory(mimeType string, category Category) error {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return fmt.Errorf("mime: invalid mime type %q: %v", mimeType, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.categories[mediaType] = category
+	return nil
+}
+
+//Category classifies the mime type, parameters such as charset are ignored
+func (r *Registry) Category(mimeType string) Category {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return CategoryOther
+	}
+
+	r.mu.RLock()
+	category, ok := r.categories[mediaType]
+	r.mu.RUnlock()
+	if ok {
+		return category
+	}
+
+	switch {
+	case documentMimeTypes[mediaType] || strings.HasPrefix(mediaType, "application/vnd.oasis.opendocument.") ||
+		strings.HasPrefix(mediaType, "application/vnd.openxmlformats-officedocument."):
+		return CategoryDocument
+	case archiveMimeTypes[mediaType]:
+		return CategoryArchive
+	case strings.HasPrefix(mediaType, "image/"):
+		return CategoryImage
+	case strings.HasPrefix(mediaType, "video/"), mediaType == "application/ogg":
+		return CategoryVideo
+	case strings.HasPrefix(mediaType, "audio/"):
+		return CategoryAudio
+	case strings.HasPrefix(mediaType, "text/"):
+		return CategoryText
+	}
+	return CategoryOther
+}
+
+//ParseMediaType parses a Content-Type value, returning the lower case media type and its parameters
+func ParseMediaType(value string) (string, map[string]string, error) {
+	return mime.ParseMediaType(value)
+}
+
+//Charset returns the charset parameter of a Content-Type value, or an empty string
+func Charset(value string) string {
+	_, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(params["charset"])
+}
+
+//normalizeExtension lower cases the extension and removes its dot
+func normalizeExtension(extension string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+}
+
+//removeString returns the values without value
+func removeString(values []string, value string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package mime_utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRegistryReverseLookup(t *testing.T) {
+	// act
+	extensions := DefaultRegistry.ExtensionsByType("image/jpeg; charset=binary")
+	preferred, ok := DefaultRegistry.ExtensionByType("image/png")
+	_, unknown := DefaultRegistry.ExtensionByType("image/unknown")
+
+	// assert
+	assert.Equal(t, []string{"jpeg", "jpg"}, extensions)
+	assert.True(t, ok)
+	assert.Equal(t, "png", preferred)
+	assert.False(t, unknown)
+}
+
+func TestRegistryRegisterSuccessful(t *testing.T) {
+	// arrange
+	registry := NewRegistry()
+
+	// act
+	err := registry.Register("application/vnd.acme+json", ".ACME", "acmejson")
+	mimeType, ok := registry.TypeByExtension("acme")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "application/vnd.acme+json", mimeType)
+	assert.Equal(t, "application/vnd.acme+json", registry.TypeByFileName("/tmp/Data.Acme"))
+	assert.Equal(t, []string{"acme", "acmejson"}, registry.ExtensionsByType("application/vnd.acme+json"))
+}
+
+func TestRegistryRegisterMovesExtension(t *testing.T) {
+	// arrange
+	registry := NewRegistry()
+	_ = registry.Register("audio/mp3", "mp3")
+
+	// act
+	err := registry.Register("audio/mpeg", "mp3")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "audio/mpeg", registry.TypeByFileName("song.mp3"))
+	assert.Empty(t, registry.ExtensionsByType("audio/mp3"))
+}
+
+func TestRegistryRegisterInvalid(t *testing.T) {
+	// arrange
+	registry := NewRegistry()
+
+	// act
+	typeErr := registry.Register("not a type", "x")
+	extensionErr := registry.Register("text/plain", ".")
+
+	// assert
+	assert.NotNil(t, typeErr)
+	assert.NotNil(t, extensionErr)
+}
+
+func TestRegistryLoadMimeTypesFile(t *testing.T) {
+	// arrange
+	content := `# comment line
+application/vnd.acme.report    acr acmer
+image/x-acme  acmi # trailing comment
+
+application/x-no-extensions
+`
+	path := filepath.Join(t.TempDir(), "mime.types")
+	_ = ioutil.WriteFile(path, []byte(content), 0644)
+	registry := NewRegistry()
+
+	// act
+	err := registry.LoadFile(path)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "application/vnd.acme.report", registry.TypeByFileName("q1.acmer"))
+	assert.Equal(t, "image/x-acme", registry.TypeByFileName("logo.acmi"))
+	assert.False(t, registry.IsKnown("application/x-no-extensions"))
+}
+
+func TestRegistryLoadInvalidLine(t *testing.T) {
+	// act
+	err := NewRegistry().Load(strings.NewReader("text/plain txt\n/// bad\n"))
+	missingErr := NewRegistry().LoadFile(filepath.Join(os.TempDir(), "missing", "mime.types"))
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+	assert.NotNil(t, missingErr)
+}
+
+func TestRegistryConcurrentRegistration(t *testing.T) {
+	// arrange
+	registry := NewDefaultRegistry()
+	var wg sync.WaitGroup
+
+	// act
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = registry.Register("application/x-custom", "c"+strings.Repeat("x", i))
+			_ = registry.TypeByFileName("file.png")
+			_ = registry.ExtensionsByType("application/x-custom")
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	assert.EqualValues(t, 20, len(registry.ExtensionsByType("application/x-custom")))
+}
+
+func TestRegistryCategory(t *testing.T) {
+	// arrange
+	registry := NewDefaultRegistry()
+	_ = registry.SetCategory("application/x-custom", CategoryDocument)
+
+	// act & assert
+	assert.Equal(t, CategoryImage, registry.Category(registry.TypeByFileName("a.png")))
+	assert.Equal(t, CategoryVideo, registry.Category(registry.TypeByFileName("a.mp4")))
+	assert.Equal(t, CategoryAudio, registry.Category(registry.TypeByFileName("a.flac")))
+	assert.Equal(t, CategoryDocument, registry.Category(registry.TypeByFileName("a.pdf")))
+	assert.Equal(t, CategoryDocument, registry.Category(registry.TypeByFileName("a.docx")))
+	assert.Equal(t, CategoryDocument, registry.Category(registry.TypeByFileName("a.odt")))
+	assert.Equal(t, CategoryArchive, registry.Category(registry.TypeByFileName("a.zip")))
+	assert.Equal(t, CategoryArchive, registry.Category(registry.TypeByFileName("a.gz")))
+	assert.Equal(t, CategoryText, registry.Category("text/plain; charset=utf-8"))
+	assert.Equal(t, CategoryDocument, registry.Category("application/x-custom"))
+	assert.Equal(t, CategoryOther, registry.Category(registry.TypeByFileName("a.exe")))
+}
+
+func TestCharset(t *testing.T) {
+	// act & assert
+	assert.Equal(t, "utf-8", Charset("text/html; charset=UTF-8"))
+	assert.Equal(t, "", Charset("application/json"))
+	assert.Equal(t, "", Charset("not a type"))
+}
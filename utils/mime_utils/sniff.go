@@ -0,0 +1,314 @@
+package mime_utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+//Confidence how much a detection or a mismatch can be trusted
+type Confidence int
+
+const (
+	ConfidenceNone Confidence = iota
+	ConfidenceLow
+	ConfidenceMedium
+	ConfidenceHigh
+
+	// SniffLength number of leading bytes needed by the signatures, tar has its magic at offset 257
+	SniffLength = 512
+)
+
+//String returns the name of the confidence level
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	}
+	return "none"
+}
+
+//Detection file type detected from the content
+type Detection struct {
+	MediaType string
+	// Extensions extensions of the formats sharing the signature, such as the zip based docx and jar
+	Extensions []string
+	Executable bool
+	Confidence Confidence
+}
+
+//HasExtension checks if the extension, without the dot, belongs to the detected format
+func (d *Detection) HasExtension(extension string) bool {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	for _, e := range d.Extensions {
+		if e == extension {
+			return true
+		}
+	}
+	return false
+}
+
+//MatchesMediaType checks if the media type is the detected one, or the registered type of one of its extensions
+func (d *Detection) MatchesMediaType(mediaType string) bool {
+	if mediaType == d.MediaType {
+		return true
+	}
+	for _, extension := range d.Extensions {
+		if registered, ok := DefaultRegistry.TypeByExtension(extension); ok && registered == mediaType && mediaType != octetStream {
+			return true
+		}
+	}
+	return false
+}
+
+//magic bytes expected at an offset
+type magic struct {
+	offset int
+	bytes  string
+}
+
+//signature file format recognised by all of its magics
+type signature struct {
+	mediaType  string
+	extensions []string
+	magics     []magic
+	confidence Confidence
+	executable bool
+	// optional the magic is not required by the extensions, such as the shebang of scripts
+	optional bool
+	// verify checks the structure behind a short magic, content failing it is only detected with a low confidence
+	verify func(data []byte) bool
+}
+
+//matches checks every magic of the signature against the content
+func (s *signature) matches(data []byte) bool {
+	for _, m := range s.magics {
+		end := m.offset + len(m.bytes)
+		if len(data) < end || string(data[m.offset:end]) != m.bytes {
+			return false
+		}
+	}
+	return true
+}
+
+//sig builds a signature from its magics
+func sig(mediaType string, extensions []string, confidence Confidence, magics ...magic) signature {
+	return signature{mediaType: mediaType, extensions: extensions, magics: magics, confidence: confidence}
+}
+
+//exe builds an executable signature
+func exe(mediaType string, extensions []string, magics ...magic) signature {
+	return signature{mediaType: mediaType, extensions: extensions, magics: magics, confidence: ConfidenceHigh, executable: true}
+}
+
+//isPortableExecutable checks that the DOS header points through e_lfanew, at 0x3C, to a PE signature within data.
+//Executables whose PE header lies beyond SniffLength are not verified.
+func isPortableExecutable(data []byte) bool {
+	if len(data) < 0x40 {
+		return false
+	}
+	offset := binary.LittleEndian.Uint32(data[0x3C:0x40])
+	return offset >= 0x40 && uint64(offset)+4 <= uint64(len(data)) && string(data[offset:offset+4]) == "PE\x00\x00"
+}
+
+//at magic at the start of the content
+func at(bytes string) magic {
+	return magic{bytes: bytes}
+}
+
+var (
+	zipExtensions  = []string{"zip", "docx", "xlsx", "pptx", "odt", "ods", "odp", "odg", "epub", "jar", "war", "ear", "apk", "xpi", "kmz", "ipa"}
+	tiffExtensions = []string{"tif", "tiff", "dng", "cr2", "nef", "nrw", "arw", "sr2", "srw", "pef", "erf", "3fr", "kdc", "dcr", "mos", "mef"}
+	mp3Extensions  = []string{"mp3", "mpeg", "mpga"}
+
+	// more specific signatures come before the generic ones sharing their magic
+	signatures = []signature{
+		// images
+		sig("image/png", []string{"png"}, ConfidenceHigh, at("\x89PNG\r\n\x1a\n")),
+		sig("image/jpeg", []string{"jpg", "jpeg", "jpe", "jfif"}, ConfidenceHigh, at("\xff\xd8\xff")),
+		sig("image/gif", []string{"gif"}, ConfidenceHigh, at("GIF87a")),
+		sig("image/gif", []string{"gif"}, ConfidenceHigh, at("GIF89a")),
+		sig("image/webp", []string{"webp"}, ConfidenceHigh, at("RIFF"), magic{8, "WEBP"}),
+		sig("image/tiff", tiffExtensions, ConfidenceMedium, at("II*\x00")),
+		sig("image/tiff", tiffExtensions, ConfidenceMedium, at("MM\x00*")),
+		sig("image/heic", []string{"heic", "heif"}, ConfidenceHigh, magic{4, "ftypheic"}),
+		sig("image/heic", []string{"heic", "heif"}, ConfidenceHigh, magic{4, "ftypheix"}),
+		sig("image/heif", []string{"heic", "heif"}, ConfidenceHigh, magic{4, "ftypmif1"}),
+		sig("image/avif", []string{"avif"}, ConfidenceHigh, magic{4, "ftypavif"}),
+		sig("image/vnd.adobe.photoshop", []string{"psd"}, ConfidenceHigh, at("8BPS")),
+		sig("image/x-icon", []string{"ico"}, ConfidenceMedium, at("\x00\x00\x01\x00")),
+		sig("image/bmp", []string{"bmp"}, ConfidenceLow, at("BM")),
+
+		// documents
+		sig("application/pdf", []string{"pdf", "ai"}, ConfidenceHigh, at("%PDF-")),
+		sig("application/postscript", []string{"ps", "eps", "ai"}, ConfidenceMedium, at("%!PS")),
+		sig("application/rtf", []string{"rtf"}, ConfidenceHigh, at("{\\rtf")),
+		sig("application/x-ole-storage", []string{"doc", "xls", "ppt", "pps", "msg", "msi", "msp", "msm"}, ConfidenceHigh, at("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")),
+
+		// archives
+		sig("application/zip", zipExtensions, ConfidenceMedium, at("PK\x03\x04")),
+		sig("application/zip", zipExtensions, ConfidenceMedium, at("PK\x05\x06")),
+		sig("application/x-7z-compressed", []string{"7z"}, ConfidenceHigh, at("7z\xbc\xaf\x27\x1c")),
+		sig("application/vnd.rar", []string{"rar"}, ConfidenceHigh, at("Rar!\x1a\x07")),
+		sig("application/x-xz", []string{"xz", "txz"}, ConfidenceHigh, at("\xfd7zXZ\x00")),
+		sig("application/x-tar", []string{"tar"}, ConfidenceHigh, magic{257, "ustar"}),
+		sig("application/vnd.ms-cab-compressed", []string{"cab"}, ConfidenceHigh, at("MSCF")),
+		sig("application/gzip", []string{"gz", "tgz", "svgz"}, ConfidenceMedium, at("\x1f\x8b")),
+		sig("application/x-bzip2", []string{"bz2", "tbz2"}, ConfidenceMedium, at("BZh")),
+
+		// audio and video
+		sig("audio/wave", []string{"wav"}, ConfidenceHigh, at("RIFF"), magic{8, "WAVE"}),
+		sig("video/x-msvideo", []string{"avi"}, ConfidenceHigh, at("RIFF"), magic{8, "AVI "}),
+		sig("audio/x-aiff", []string{"aif", "aiff"}, ConfidenceHigh, at("FORM"), magic{8, "AIFF"}),
+		sig("audio/ogg", []string{"ogg", "oga", "ogv", "opus", "spx"}, ConfidenceHigh, at("OggS")),
+		sig("audio/flac", []string{"flac"}, ConfidenceHigh, at("fLaC")),
+		sig("audio/midi", []string{"mid", "midi", "kar"}, ConfidenceHigh, at("MThd")),
+		sig("video/quicktime", []string{"mov"}, ConfidenceHigh, magic{4, "ftypqt  "}),
+		sig("audio/mp4", []string{"m4a"}, ConfidenceHigh, magic{4, "ftypM4A "}),
+		sig("video/3gpp", []string{"3gp", "3gpp", "3g2"}, ConfidenceHigh, magic{4, "ftyp3g"}),
+		sig("video/mp4", []string{"mp4", "m4v", "m4a", "mov", "3gp", "3gpp"}, ConfidenceMedium, magic{4, "ftyp"}),
+		sig("video/x-matroska", []string{"mkv", "mka", "webm"}, ConfidenceMedium, at("\x1a\x45\xdf\xa3")),
+		sig("video/x-flv", []string{"flv"}, ConfidenceHigh, at("FLV\x01")),
+		sig("video/x-ms-asf", []string{"asf", "wmv", "wma"}, ConfidenceHigh, at("\x30\x26\xb2\x75\x8e\x66\xcf\x11")),
+		sig("video/mpeg", []string{"mpg", "mpeg"}, ConfidenceMedium, at("\x00\x00\x01\xba")),
+		sig("audio/mpeg", mp3Extensions, ConfidenceMedium, at("ID3")),
+		sig("audio/mpeg", mp3Extensions, ConfidenceLow, at("\xff\xfb")),
+		sig("audio/mpeg", mp3Extensions, ConfidenceLow, at("\xff\xf3")),
+		sig("audio/mpeg", mp3Extensions, ConfidenceLow, at("\xff\xf2")),
+		sig("audio/mpeg", mp3Extensions, ConfidenceLow, at("\xff\xfa")),
+
+		// fonts and flash
+		sig("font/woff", []string{"woff"}, ConfidenceHigh, at("wOFF")),
+		sig("font/woff2", []string{"woff2"}, ConfidenceHigh, at("wOF2")),
+		sig("application/x-shockwave-flash", []string{"swf"}, ConfidenceMedium, at("FWS")),
+		sig("application/x-shockwave-flash", []string{"swf"}, ConfidenceMedium, at("CWS")),
+		sig("application/x-shockwave-flash", []string{"swf"}, ConfidenceMedium, at("ZWS")),
+
+		// executables
+		// text such as a csv starting with MZ shares the two bytes, only a PE header makes it trustworthy
+		{mediaType: "application/x-msdownload", extensions: []string{"exe", "dll", "sys", "scr", "cpl", "ocx", "efi"}, magics: []magic{at("MZ")}, confidence: ConfidenceHigh, executable: true, verify: isPortableExecutable},
+		exe("application/x-executable", []string{"so", "o", "ko", "elf"}, at("\x7fELF")),
+		exe("application/x-mach-binary", []string{"dylib", "bundle"}, at("\xfe\xed\xfa\xce")),
+		exe("application/x-mach-binary", []string{"dylib", "bundle"}, at("\xfe\xed\xfa\xcf")),
+		exe("application/x-mach-binary", []string{"dylib", "bundle"}, at("\xce\xfa\xed\xfe")),
+		exe("application/x-mach-binary", []string{"dylib", "bundle"}, at("\xcf\xfa\xed\xfe")),
+		// universal mach-o binaries and java classes share their magic
+		exe("application/x-mach-binary", []string{"class", "dylib", "bundle"}, at("\xca\xfe\xba\xbe")),
+		exe("application/wasm", []string{"wasm"}, at("\x00asm")),
+		exe("application/vnd.android.dex", []string{"dex"}, at("dex\n")),
+		{mediaType: "text/x-shellscript", extensions: []string{"sh", "bash", "zsh", "py", "pl", "pm", "rb", "tcl", "tk", "run"}, magics: []magic{at("#!")}, confidence: ConfidenceMedium, executable: true, optional: true},
+	}
+)
+
+//Detect detects the file type from its first bytes, at least SniffLength when available
+func Detect(data []byte) (*Detection, bool) {
+	for i := range signatures {
+		s := &signatures[i]
+		if s.matches(data) {
+			confidence := s.confidence
+			if s.verify != nil && !s.verify(data) {
+				confidence = ConfidenceLow
+			}
+			return &Detection{
+				MediaType:  s.mediaType,
+				Extensions: s.extensions,
+				Executable: s.executable,
+				Confidence: confidence,
+			}, true
+		}
+	}
+	return nil, false
+}
+
+//DetectReader detects the file type from the start of the reader. The returned reader replays the bytes
+//that were read, and must be used instead of r afterwards.
+func DetectReader(r io.Reader) (*Detection, bool, io.Reader, error) {
+	head, replay, err := readHead(r)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	detection, ok := Detect(head)
+	return detection, ok, replay, nil
+}
+
+//ExtensionCheck result of comparing the extension of a file name with its content
+type ExtensionCheck struct {
+	Extension string
+	// Detection nil when the content was not recognised
+	Detection *Detection
+	Mismatch  bool
+	// Confidence how likely the mismatch is real, or how reliable the detection is when there is no mismatch
+	Confidence Confidence
+}
+
+//CheckExtension reports when the content contradicts the extension of the file name, such as an executable
+//renamed to photo.jpg or a .png whose bytes are not a png
+func CheckExtension(fileName string, data []byte) ExtensionCheck {
+	check := ExtensionCheck{Extension: strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), "."))}
+
+	detection, ok := Detect(data)
+	if !ok {
+		// content that no signature recognises contradicts extensions of formats that have one
+		if check.Extension != "" && hasSignature(check.Extension) {
+			check.Mismatch = true
+			check.Confidence = ConfidenceMedium
+		}
+		return check
+	}
+
+	check.Detection = detection
+	check.Confidence = detection.Confidence
+	if check.Extension == "" || detection.HasExtension(check.Extension) {
+		return check
+	}
+
+	check.Mismatch = true
+	if detection.Executable && detection.Confidence > ConfidenceLow {
+		// executables disguised under another extension are what this check exists for, unless only a short magic
+		// without the structure behind it was found
+		check.Confidence = ConfidenceHigh
+	}
+	return check
+}
+
+//CheckExtensionReader checks the extension against the start of the reader, returning a reader that replays it
+func CheckExtensionReader(fileName string, r io.Reader) (ExtensionCheck, io.Reader, error) {
+	head, replay, err := readHead(r)
+	if err != nil {
+		return ExtensionCheck{}, nil, err
+	}
+	return CheckExtension(fileName, head), replay, nil
+}
+
+//readHead reads up to SniffLength bytes, returning them and a reader replaying them before the rest of r
+func readHead(r io.Reader) ([]byte, io.Reader, error) {
+	head := make([]byte, SniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	head = head[:n]
+	return head, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+//hasSignature checks if the extension requires the magic of a signature
+func hasSignature(extension string) bool {
+	for i := range signatures {
+		if signatures[i].optional {
+			continue
+		}
+		for _, e := range signatures[i].extensions {
+			if e == extension {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package mime_utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//peHeader DOS header whose e_lfanew points to a PE signature
+var peHeader = append(append(append([]byte("MZ\x90\x00\x03\x00"), make([]byte, 0x36)...), 0x40, 0x00, 0x00, 0x00), "PE\x00\x00"...)
+
+func TestDetectKnownFormats(t *testing.T) {
+	// arrange
+	tar := make([]byte, 300)
+	copy(tar[257:], "ustar")
+	tests := map[string][]byte{
+		"image/png":                pngHeader,
+		"image/jpeg":               []byte("\xff\xd8\xff\xe0\x00\x10JFIF"),
+		"image/gif":                []byte("GIF89a\x01\x00"),
+		"image/webp":               []byte("RIFF\x00\x00\x00\x00WEBPVP8 "),
+		"application/pdf":          []byte("%PDF-1.7\n"),
+		"application/zip":          []byte("PK\x03\x04\x14\x00"),
+		"application/x-tar":        tar,
+		"audio/wave":               []byte("RIFF\x00\x00\x00\x00WAVEfmt "),
+		"video/quicktime":          []byte("\x00\x00\x00\x14ftypqt  "),
+		"video/mp4":                []byte("\x00\x00\x00\x18ftypisom"),
+		"application/x-msdownload": peHeader,
+		"application/x-executable": []byte("\x7fELF\x02\x01\x01"),
+	}
+
+	for expected, data := range tests {
+		// act
+		detection, ok := Detect(data)
+
+		// assert
+		assert.True(t, ok, expected)
+		assert.Equal(t, expected, detection.MediaType)
+	}
+}
+
+func TestDetectUnknownContent(t *testing.T) {
+	// act
+	detection, ok := Detect([]byte("just some text"))
+
+	// assert
+	assert.False(t, ok)
+	assert.Nil(t, detection)
+}
+
+func TestDetectReaderReplaysContent(t *testing.T) {
+	// arrange
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{1}, 2000)...)
+
+	// act
+	detection, ok, replay, err := DetectReader(bytes.NewReader(content))
+	read, _ := ioutil.ReadAll(replay)
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "image/png", detection.MediaType)
+	assert.Equal(t, content, read)
+}
+
+func TestCheckExtensionExecutableDisguisedAsImage(t *testing.T) {
+	// act
+	check := CheckExtension("photo.JPG", peHeader)
+
+	// assert
+	assert.True(t, check.Mismatch)
+	assert.Equal(t, ConfidenceHigh, check.Confidence)
+	assert.Equal(t, "jpg", check.Extension)
+	assert.True(t, check.Detection.Executable)
+}
+
+func TestCheckExtensionTextStartingWithMZ(t *testing.T) {
+	// arrange
+	csv := []byte("MZ,Mozambique\nZA,South Africa\n")
+
+	// act
+	detection, ok := Detect(csv)
+	check := CheckExtension("countries.csv", csv)
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, ConfidenceLow, detection.Confidence)
+	assert.True(t, check.Mismatch)
+	assert.Equal(t, ConfidenceLow, check.Confidence)
+}
+
+func TestCheckExtensionMatchingContent(t *testing.T) {
+	// act
+	png := CheckExtension("photo.png", pngHeader)
+	docx := CheckExtension("report.docx", []byte("PK\x03\x04\x14\x00"))
+	text := CheckExtension("notes.txt", []byte("hello"))
+
+	// assert
+	assert.False(t, png.Mismatch)
+	assert.Equal(t, ConfidenceHigh, png.Confidence)
+	assert.False(t, docx.Mismatch)
+	assert.Equal(t, ConfidenceMedium, docx.Confidence)
+	assert.False(t, text.Mismatch)
+	assert.Equal(t, ConfidenceNone, text.Confidence)
+}
+
+func TestCheckExtensionContradictingContent(t *testing.T) {
+	// act
+	wrongImage := CheckExtension("photo.png", []byte("\xff\xd8\xff\xe0"))
+	notAnImage := CheckExtension("photo.png", []byte("<html></html>"))
+	weak := CheckExtension("photo.png", []byte("BM\x00\x00"))
+
+	// assert
+	assert.True(t, wrongImage.Mismatch)
+	assert.Equal(t, ConfidenceHigh, wrongImage.Confidence)
+	assert.True(t, notAnImage.Mismatch)
+	assert.Equal(t, ConfidenceMedium, notAnImage.Confidence)
+	assert.Nil(t, notAnImage.Detection)
+	assert.True(t, weak.Mismatch)
+	assert.Equal(t, ConfidenceLow, weak.Confidence)
+}
+
+func TestCheckExtensionScriptWithoutShebang(t *testing.T) {
+	// act
+	check := CheckExtension("deploy.sh", []byte("echo hello"))
+
+	// assert
+	assert.False(t, check.Mismatch)
+}
+
+func TestCheckExtensionReaderSuccessful(t *testing.T) {
+	// act
+	check, replay, err := CheckExtensionReader("archive.zip", bytes.NewReader([]byte("\x7fELF\x02\x01\x01")))
+	read, _ := ioutil.ReadAll(replay)
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, check.Mismatch)
+	assert.Equal(t, "application/x-executable", check.Detection.MediaType)
+	assert.Equal(t, []byte("\x7fELF\x02\x01\x01"), read)
+}
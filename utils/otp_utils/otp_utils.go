@@ -0,0 +1,313 @@
+package otp_utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/random_utils"
+)
+
+//Algorithm HMAC algorithm of the one time passwords
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+
+	DefaultDigits       = 6
+	DefaultPeriod       = 30 * time.Second
+	DefaultSkew         = 1
+	DefaultSecretLength = 20
+)
+
+var (
+	ErrInvalidSecret    = errors.New("otp: secret must be base32 encoded")
+	ErrInvalidDigits    = errors.New("otp: digits must be between 6 and 8")
+	ErrInvalidPeriod    = errors.New("otp: period must be a whole number of seconds")
+	ErrInvalidAlgorithm = errors.New("otp: unsupported algorithm")
+	ErrInvalidCode      = errors.New("otp: invalid code")
+	ErrCodeReused       = errors.New("otp: code has already been used")
+
+	secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+//Options one time password settings, zero values use the defaults that authenticator apps expect
+//and nil options also accept DefaultSkew steps of clock drift
+type Options struct {
+	// Digits length of the codes, 6 to 8
+	Digits int
+	// Period validity of a TOTP code
+	Period time.Duration
+	// Algorithm HMAC algorithm, SHA1 is the only one supported by every authenticator app
+	Algorithm Algorithm
+	// Skew number of steps accepted before and after the current one for TOTP, or after the counter for HOTP,
+	// 0 only accepts the current one
+	Skew int
+}
+
+//withDefaults returns a copy of the options with the defaults filled in
+func (o *Options) withDefaults() (Options, error) {
+	options := Options{Skew: DefaultSkew}
+	if o != nil {
+		options = *o
+	}
+
+	if options.Digits == 0 {
+		options.Digits = DefaultDigits
+	}
+	if options.Period == 0 {
+		options.Period = DefaultPeriod
+	}
+	if options.Algorithm == "" {
+		options.Algorithm = AlgorithmSHA1
+	}
+	if options.Skew < 0 {
+		options.Skew = 0
+	}
+
+	if options.Digits < 6 || options.Digits > 8 {
+		return options, ErrInvalidDigits
+	}
+	if options.Period < time.Second || options.Period%time.Second != 0 {
+		return options, ErrInvalidPeriod
+	}
+	if _, err := options.Algorithm.hash(); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+//hash returns the hash constructor of the algorithm
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	}
+	return nil, ErrInvalidAlgorithm
+}
+
+//GenerateSecret returns a new base32 encoded secret of length random bytes, DefaultSecretLength when 0
+func GenerateSecret(length int) (string, error) {
+	if length <= 0 {
+		length = DefaultSecretLength
+	}
+
+	secret, err := random_utils.NewRandomBytes(length)
+	if err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+//decodeSecret decodes a base32 secret, ignoring case, spaces and padding
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	key, err := secretEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+//GenerateHOTP returns the RFC 4226 code for the counter
+func GenerateHOTP(secret string, counter uint64, options *Options) (string, error) {
+	opts, err := options.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, counter, opts)
+}
+
+//ValidateHOTP validates the code against the counter and the next Skew counters,
+//returning the counter to store for the next validation
+func ValidateHOTP(secret string, code string, counter uint64, options *Options) (uint64, error) {
+	opts, err := options.withDefaults()
+	if err != nil {
+		return counter, err
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return counter, err
+	}
+
+	for i := 0; i <= opts.Skew; i++ {
+		expected, err := hotp(key, counter+uint64(i), opts)
+		if err != nil {
+			return counter, err
+		}
+		if codesEqual(expected, code) {
+			return counter + uint64(i) + 1, nil
+		}
+	}
+	return counter, ErrInvalidCode
+}
+
+//TOTP RFC 6238 time based one time passwords
+type TOTP struct {
+	options Options
+	clock   date_utils.Clock
+}
+
+//NewTOTP constructor, options may be nil for the defaults
+func NewTOTP(options *Options) (*TOTP, error) {
+	opts, err := options.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTP{
+		options: opts,
+		clock:   date_utils.RealClock{},
+	}, nil
+}
+
+//SetClock replaces the clock used by Generate and Validate
+func (t *TOTP) SetClock(clock date_utils.Clock) {
+	t.clock = clock
+}
+
+//Step returns the time step the given time falls in
+func (t *TOTP) Step(at time.Time) int64 {
+	return at.Unix() / int64(t.options.Period/time.Second)
+}
+
+//Generate returns the code for the current time
+func (t *TOTP) Generate(secret string) (string, error) {
+	return t.GenerateAt(secret, t.clock.Now())
+}
+
+//GenerateAt returns the code for the given time
+func (t *TOTP) GenerateAt(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(t.Step(at)), t.options)
+}
+
+//Validate validates the code against the current step and Skew steps around it. lastUsedStep is the step
+//returned by the previous successful validation for this secret, or 0, and codes of that step or earlier are
+//rejected so that a code cannot be used twice. The matched step is returned and must be stored by the caller.
+func (t *TOTP) Validate(secret string, code string, lastUsedStep int64) (int64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return lastUsedStep, err
+	}
+
+	current := t.Step(t.clock.Now())
+	reused := false
+	for i := -t.options.Skew; i <= t.options.Skew; i++ {
+		step := current + int64(i)
+		if step < 0 {
+			continue
+		}
+		expected, err := hotp(key, uint64(step), t.options)
+		if err != nil {
+			return lastUsedStep, err
+		}
+		if !codesEqual(expected, code) {
+			continue
+		}
+		if step <= lastUsedStep {
+			reused = true
+			continue
+		}
+		return step, nil
+	}
+
+	if reused {
+		return lastUsedStep, ErrCodeReused
+	}
+	return lastUsedStep, ErrInvalidCode
+}
+
+//ProvisioningURI returns the otpauth:// URI to show as a QR code to the user
+func (t *TOTP) ProvisioningURI(secret string, issuer string, accountName string) string {
+	return provisioningURI("totp", secret, issuer, accountName, t.options, url.Values{
+		"period": []string{strconv.Itoa(int(t.options.Period / time.Second))},
+	})
+}
+
+//HOTPProvisioningURI returns the otpauth:// URI of a counter based secret
+func HOTPProvisioningURI(secret string, issuer string, accountName string, counter uint64, options *Options) (string, error) {
+	opts, err := options.withDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	return provisioningURI("hotp", secret, issuer, accountName, opts, url.Values{
+		"counter": []string{strconv.FormatUint(counter, 10)},
+	}), nil
+}
+
+//provisioningURI builds the key URI, see https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func provisioningURI(otpType string, secret string, issuer string, accountName string, options Options, query url.Values) string {
+	label := accountName
+	if issuer != "" {
+		label = issuer + ":" + accountName
+		query.Set("issuer", issuer)
+	}
+	query.Set("secret", strings.TrimRight(strings.ToUpper(secret), "="))
+	query.Set("algorithm", string(options.Algorithm))
+	query.Set("digits", strconv.Itoa(options.Digits))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     otpType,
+		Path:     "/" + label,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+//hotp computes the code with the dynamic truncation of RFC 4226 section 5.3
+func hotp(key []byte, counter uint64, options Options) (string, error) {
+	hashFunc, err := options.Algorithm.hash()
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+
+	mac := hmac.New(hashFunc, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < options.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", options.Digits, value%modulo), nil
+}
+
+//codesEqual compares codes in constant time, ignoring spaces users may type
+func codesEqual(expected string, code string) bool {
+	code = strings.Replace(code, " ", "", -1)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1
+}
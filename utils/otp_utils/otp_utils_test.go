@@ -0,0 +1,183 @@
+package otp_utils
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// secrets of the RFC 4226 and RFC 6238 test vectors
+var (
+	rfcSecretSHA1   = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	rfcSecretSHA256 = base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012"))
+	rfcSecretSHA512 = base32.StdEncoding.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234"))
+)
+
+func newTestTOTP(t *testing.T, options *Options, now time.Time) *TOTP {
+	totp, err := NewTOTP(options)
+	assert.Nil(t, err)
+	totp.SetClock(date_utils.NewFakeClock(now))
+	return totp
+}
+
+func TestGenerateSecretSuccessful(t *testing.T) {
+	// act
+	secret, err := GenerateSecret(0)
+	key, decodeErr := decodeSecret(secret)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, decodeErr)
+	assert.EqualValues(t, DefaultSecretLength, len(key))
+	assert.EqualValues(t, 32, len(secret))
+}
+
+func TestGenerateHOTPMatchesRFC4226(t *testing.T) {
+	// arrange
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range expected {
+		// act
+		actual, err := GenerateHOTP(rfcSecretSHA1, uint64(counter), nil)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, code, actual)
+	}
+}
+
+func TestGenerateTOTPMatchesRFC6238(t *testing.T) {
+	// arrange
+	tests := []struct {
+		unix      int64
+		algorithm Algorithm
+		secret    string
+		code      string
+	}{
+		{59, AlgorithmSHA1, rfcSecretSHA1, "94287082"},
+		{59, AlgorithmSHA256, rfcSecretSHA256, "46119246"},
+		{59, AlgorithmSHA512, rfcSecretSHA512, "90693936"},
+		{1111111109, AlgorithmSHA1, rfcSecretSHA1, "07081804"},
+		{1234567890, AlgorithmSHA256, rfcSecretSHA256, "91819424"},
+		{20000000000, AlgorithmSHA512, rfcSecretSHA512, "47863826"},
+	}
+
+	for _, test := range tests {
+		totp, err := NewTOTP(&Options{Digits: 8, Algorithm: test.algorithm})
+		assert.Nil(t, err)
+
+		// act
+		code, err := totp.GenerateAt(test.secret, time.Unix(test.unix, 0))
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, test.code, code)
+	}
+}
+
+func TestNewTOTPInvalidOptions(t *testing.T) {
+	// act
+	_, digitsErr := NewTOTP(&Options{Digits: 4})
+	_, periodErr := NewTOTP(&Options{Period: 1500 * time.Millisecond})
+	_, algorithmErr := NewTOTP(&Options{Algorithm: "MD5"})
+
+	// assert
+	assert.Equal(t, ErrInvalidDigits, digitsErr)
+	assert.Equal(t, ErrInvalidPeriod, periodErr)
+	assert.Equal(t, ErrInvalidAlgorithm, algorithmErr)
+}
+
+func TestTOTPValidateWithinDrift(t *testing.T) {
+	// arrange
+	now := time.Unix(1600000000, 0)
+	totp := newTestTOTP(t, nil, now)
+	previous, _ := totp.GenerateAt(rfcSecretSHA1, now.Add(-30*time.Second))
+	tooOld, _ := totp.GenerateAt(rfcSecretSHA1, now.Add(-90*time.Second))
+
+	// act
+	step, err := totp.Validate(rfcSecretSHA1, previous, 0)
+	_, tooOldErr := totp.Validate(rfcSecretSHA1, tooOld, 0)
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, totp.Step(now)-1, step)
+	assert.Equal(t, ErrInvalidCode, tooOldErr)
+}
+
+func TestTOTPValidateWithoutDrift(t *testing.T) {
+	// arrange
+	now := time.Unix(1600000000, 0)
+	totp := newTestTOTP(t, &Options{Skew: 0}, now)
+	previous, _ := totp.GenerateAt(rfcSecretSHA1, now.Add(-30*time.Second))
+
+	// act
+	_, err := totp.Validate(rfcSecretSHA1, previous, 0)
+
+	// assert
+	assert.Equal(t, ErrInvalidCode, err)
+}
+
+func TestTOTPValidateRejectsReuse(t *testing.T) {
+	// arrange
+	now := time.Unix(1600000000, 0)
+	totp := newTestTOTP(t, nil, now)
+	code, _ := totp.Generate(rfcSecretSHA1)
+
+	// act
+	step, firstErr := totp.Validate(rfcSecretSHA1, code, 0)
+	_, reuseErr := totp.Validate(rfcSecretSHA1, code, step)
+
+	// assert
+	assert.Nil(t, firstErr)
+	assert.Equal(t, ErrCodeReused, reuseErr)
+}
+
+func TestValidateHOTPLookAhead(t *testing.T) {
+	// arrange
+	code, _ := GenerateHOTP(rfcSecretSHA1, 6, nil)
+
+	// act
+	next, err := ValidateHOTP(rfcSecretSHA1, code, 5, nil)
+	_, behindErr := ValidateHOTP(rfcSecretSHA1, code, 7, nil)
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 7, next)
+	assert.Equal(t, ErrInvalidCode, behindErr)
+}
+
+func TestProvisioningURISuccessful(t *testing.T) {
+	// arrange
+	totp := newTestTOTP(t, &Options{Digits: 8, Algorithm: AlgorithmSHA256}, time.Now())
+
+	// act
+	uri := totp.ProvisioningURI("JBSWY3DPEHPK3PXP", "Acme Co", "admin@example.com")
+	parsed, err := url.Parse(uri)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Acme Co:admin@example.com", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Acme Co", parsed.Query().Get("issuer"))
+	assert.Equal(t, "SHA256", parsed.Query().Get("algorithm"))
+	assert.Equal(t, "8", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
+
+func TestHOTPProvisioningURISuccessful(t *testing.T) {
+	// act
+	uri, err := HOTPProvisioningURI("JBSWY3DPEHPK3PXP", "", "admin", 42, nil)
+	parsed, _ := url.Parse(uri)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "hotp", parsed.Host)
+	assert.Equal(t, "42", parsed.Query().Get("counter"))
+	assert.Empty(t, parsed.Query().Get("issuer"))
+}
@@ -0,0 +1,72 @@
+package otp_utils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lelinu/api_utils/utils/crypto_utils"
+	"github.com/lelinu/api_utils/utils/random_utils"
+)
+
+const (
+	DefaultRecoveryCodeCount = 10
+	recoveryCodeLength       = 10
+)
+
+var ErrInvalidRecoveryCode = errors.New("otp: invalid recovery code")
+
+//recoveryCodeReplacer removes the separators and spaces users may type and maps the characters Crockford base32
+//leaves out to the digits they are mistaken for
+var recoveryCodeReplacer = strings.NewReplacer("-", "", " ", "", "I", "1", "L", "1", "O", "0")
+
+//GenerateRecoveryCodes returns count one time recovery codes shaped like ABCDE-12345 over the Crockford base32
+//alphabet, to show once to the user, and their hashes to store. count defaults to DefaultRecoveryCodeCount.
+func GenerateRecoveryCodes(count int, hasher crypto_utils.Hasher) ([]string, []string, error) {
+	if count <= 0 {
+		count = DefaultRecoveryCodeCount
+	}
+
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for len(codes) < count {
+		code, err := random_utils.NewCrockfordCode(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash, err := hasher.Hash(code)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+//UseRecoveryCode verifies the code against the stored hashes and returns the hashes left once the matched one
+//is removed, which the caller must store so that the code cannot be used again
+func UseRecoveryCode(code string, hashes []string, hasher crypto_utils.Hasher) ([]string, error) {
+	code = normalizeRecoveryCode(code)
+	if len(code) != recoveryCodeLength {
+		return hashes, ErrInvalidRecoveryCode
+	}
+
+	for i, hash := range hashes {
+		ok, err := hasher.Verify(hash, code)
+		if err != nil {
+			return hashes, err
+		}
+		if ok {
+			remaining := make([]string, 0, len(hashes)-1)
+			remaining = append(remaining, hashes[:i]...)
+			return append(remaining, hashes[i+1:]...), nil
+		}
+	}
+	return hashes, ErrInvalidRecoveryCode
+}
+
+//normalizeRecoveryCode brings a typed code back to the generated form, case insensitively
+func normalizeRecoveryCode(code string) string {
+	return recoveryCodeReplacer.Replace(strings.ToUpper(code))
+}
@@ -0,0 +1,64 @@
+package otp_utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lelinu/api_utils/utils/crypto_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRecoveryCodesSuccessful(t *testing.T) {
+	// arrange
+	hasher := &crypto_utils.BcryptHasher{Cost: 4}
+
+	// act
+	codes, hashes, err := GenerateRecoveryCodes(3, hasher)
+
+	// assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(codes))
+	assert.EqualValues(t, 3, len(hashes))
+	for i, code := range codes {
+		assert.Regexp(t, "^[0-9A-HJKMNP-TV-Z]{5}-[0-9A-HJKMNP-TV-Z]{5}$", code)
+		assert.NotContains(t, hashes[i], code)
+	}
+}
+
+func TestUseRecoveryCodeOnlyOnce(t *testing.T) {
+	// arrange
+	hasher := &crypto_utils.BcryptHasher{Cost: 4}
+	codes, hashes, _ := GenerateRecoveryCodes(3, hasher)
+
+	// act
+	remaining, err := UseRecoveryCode(" "+strings.ToLower(codes[1])+" ", hashes, hasher)
+	_, reuseErr := UseRecoveryCode(codes[1], remaining, hasher)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{hashes[0], hashes[2]}, remaining)
+	assert.Equal(t, ErrInvalidRecoveryCode, reuseErr)
+}
+
+func TestUseRecoveryCodeInvalid(t *testing.T) {
+	// arrange
+	hasher := &crypto_utils.BcryptHasher{Cost: 4}
+	_, hashes, _ := GenerateRecoveryCodes(2, hasher)
+
+	// act
+	remaining, err := UseRecoveryCode("aaaaa-bbbbb", hashes, hasher)
+	_, shortErr := UseRecoveryCode("abc", hashes, hasher)
+
+	// assert
+	assert.Equal(t, ErrInvalidRecoveryCode, err)
+	assert.Equal(t, hashes, remaining)
+	assert.Equal(t, ErrInvalidRecoveryCode, shortErr)
+}
+
+func TestNormalizeRecoveryCodeAmbiguousCharacters(t *testing.T) {
+	// act
+	normalized := normalizeRecoveryCode(" abcio-lo123 ")
+
+	// assert
+	assert.Equal(t, "ABC1010123", normalized)
+}
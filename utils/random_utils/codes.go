@@ -0,0 +1,144 @@
+package random_utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+const (
+	NumericAlphabet = "0123456789"
+	// CrockfordAlphabet human friendly alphabet without the ambiguous I, L, O and U
+	CrockfordAlphabet    = crockfordAlphabet
+	HexAlphabet          = "0123456789abcdef"
+	URLSafeAlphabet      = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	AlphanumericAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var (
+	ErrInvalidAlphabet = errors.New("random: alphabet must have between 2 and 256 distinct characters")
+	ErrInvalidLength   = errors.New("random: length must be greater than 0")
+
+	defaultCodeGenerator = NewCodeGenerator(nil)
+)
+
+//CodeGenerator generates random strings over any alphabet without modulo bias
+type CodeGenerator struct {
+	reader io.Reader
+}
+
+//NewCodeGenerator constructor, reader defaults to crypto/rand when nil and can be replaced for deterministic tests
+func NewCodeGenerator(reader io.Reader) *CodeGenerator {
+	if reader == nil {
+		reader = rand.Reader
+	}
+	return &CodeGenerator{reader: reader}
+}
+
+//String returns length characters drawn uniformly from the alphabet. Random bytes are masked to the smallest
+//power of two covering the alphabet and rejected when out of range, so that every character is equally likely.
+func (g *CodeGenerator) String(alphabet string, length int) (string, error) {
+	chars := []rune(alphabet)
+	if err := validateAlphabet(chars); err != nil {
+		return "", err
+	}
+	if length <= 0 {
+		return "", ErrInvalidLength
+	}
+
+	mask := byte(1<<bits.Len(uint(len(chars)-1)) - 1)
+	out := make([]rune, 0, length)
+	buffer := make([]byte, length+length/2)
+	for len(out) < length {
+		if _, err := io.ReadFull(g.reader, buffer); err != nil {
+			return "", err
+		}
+		for _, b := range buffer {
+			index := int(b & mask)
+			if index >= len(chars) {
+				continue
+			}
+			out = append(out, chars[index])
+			if len(out) == length {
+				break
+			}
+		}
+	}
+	return string(out), nil
+}
+
+//NumericCode returns a numeric one time code such as 042917, leading zeros included
+func (g *CodeGenerator) NumericCode(length int) (string, error) {
+	return g.String(NumericAlphabet, length)
+}
+
+//CrockfordCode returns an upper case code that is easy to read out and type, such as a voucher
+func (g *CodeGenerator) CrockfordCode(length int) (string, error) {
+	return g.String(CrockfordAlphabet, length)
+}
+
+//HexToken returns the hex encoding of byteLength random bytes
+func (g *CodeGenerator) HexToken(byteLength int) (string, error) {
+	if byteLength <= 0 {
+		return "", ErrInvalidLength
+	}
+
+	b := make([]byte, byteLength)
+	if _, err := io.ReadFull(g.reader, b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+//URLSafeToken returns a token of at least entropyBits bits of entropy, using the base64 url alphabet
+//so that it can be used in urls and headers as is
+func (g *CodeGenerator) URLSafeToken(entropyBits int) (string, error) {
+	if entropyBits <= 0 {
+		return "", ErrInvalidLength
+	}
+	// every character carries 6 bits
+	return g.String(URLSafeAlphabet, (entropyBits+5)/6)
+}
+
+//NewRandomStringWithAlphabet returns a random string over the alphabet, reporting random source failures
+func NewRandomStringWithAlphabet(alphabet string, length int) (string, error) {
+	return defaultCodeGenerator.String(alphabet, length)
+}
+
+//NewNumericCode returns a random numeric code
+func NewNumericCode(length int) (string, error) {
+	return defaultCodeGenerator.NumericCode(length)
+}
+
+//NewCrockfordCode returns a random Crockford base32 code
+func NewCrockfordCode(length int) (string, error) {
+	return defaultCodeGenerator.CrockfordCode(length)
+}
+
+//NewHexToken returns a random hex token of byteLength bytes
+func NewHexToken(byteLength int) (string, error) {
+	return defaultCodeGenerator.HexToken(byteLength)
+}
+
+//NewURLSafeToken returns a random url safe token of at least entropyBits bits
+func NewURLSafeToken(entropyBits int) (string, error) {
+	return defaultCodeGenerator.URLSafeToken(entropyBits)
+}
+
+//validateAlphabet checks the alphabet size and that no character is repeated, which would bias the output
+func validateAlphabet(chars []rune) error {
+	if len(chars) < 2 || len(chars) > 256 {
+		return ErrInvalidAlphabet
+	}
+
+	seen := make(map[rune]bool, len(chars))
+	for _, c := range chars {
+		if seen[c] {
+			return ErrInvalidAlphabet
+		}
+		seen[c] = true
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package random_utils
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//failingReader random source that always fails
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("entropy exhausted")
+}
+
+func TestCodeGeneratorRejectsOutOfRangeBytes(t *testing.T) {
+	//arrange
+	// masked to 4 bits, 10 to 15 are out of range for the digits and must be skipped
+	generator := NewCodeGenerator(bytes.NewReader([]byte{0x0a, 0x03, 0x0f, 0x17, 0xff, 0x09, 0x00, 0x00, 0x00}))
+
+	//act
+	code, err := generator.NumericCode(3)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "379", code)
+}
+
+func TestCodeGeneratorCustomAlphabet(t *testing.T) {
+	//arrange
+	generator := NewCodeGenerator(bytes.NewReader([]byte{0, 1, 2, 3, 4, 5}))
+
+	//act
+	code, err := generator.String("äbc", 4)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "äbcä", code)
+}
+
+func TestCodeGeneratorInvalidAlphabet(t *testing.T) {
+	//arrange
+	generator := NewCodeGenerator(nil)
+
+	//act
+	_, singleErr := generator.String("a", 4)
+	_, duplicateErr := generator.String("abca", 4)
+	_, lengthErr := generator.String("abc", 0)
+
+	//assert
+	assert.Equal(t, ErrInvalidAlphabet, singleErr)
+	assert.Equal(t, ErrInvalidAlphabet, duplicateErr)
+	assert.Equal(t, ErrInvalidLength, lengthErr)
+}
+
+func TestCodeGeneratorSurfacesReaderErrors(t *testing.T) {
+	//arrange
+	generator := NewCodeGenerator(failingReader{})
+
+	//act
+	_, codeErr := generator.NumericCode(6)
+	_, hexErr := generator.HexToken(16)
+
+	//assert
+	assert.NotNil(t, codeErr)
+	assert.NotNil(t, hexErr)
+}
+
+func TestNewNumericCode(t *testing.T) {
+	//act
+	code, err := NewNumericCode(6)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[0-9]{6}$", code)
+}
+
+func TestNewCrockfordCode(t *testing.T) {
+	//act
+	code, err := NewCrockfordCode(200)
+
+	//assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 200, len(code))
+	assert.False(t, strings.ContainsAny(code, "ILOU"))
+}
+
+func TestNewHexToken(t *testing.T) {
+	//act
+	token, err := NewHexToken(16)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[0-9a-f]{32}$", token)
+}
+
+func TestNewURLSafeToken(t *testing.T) {
+	//act
+	token, err := NewURLSafeToken(128)
+
+	//assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 22, len(token))
+	assert.True(t, regexp.MustCompile(`^[A-Za-z0-9_-]+$`).MatchString(token))
+}
+
+func TestCodeGeneratorIsUniform(t *testing.T) {
+	//arrange
+	counts := map[rune]int{}
+
+	//act
+	code, err := NewRandomStringWithAlphabet("abc", 30000)
+	for _, c := range code {
+		counts[c]++
+	}
+
+	//assert
+	assert.Nil(t, err)
+	for _, c := range "abc" {
+		assert.InDelta(t, 10000, counts[c], 600)
+	}
+}
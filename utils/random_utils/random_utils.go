@@ -8,7 +8,7 @@ import (
 )
 
 var (
-	letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+	letters = []rune(AlphanumericAlphabet)
 )
 
 func NewUUID() (string, error) {
@@ -24,6 +24,19 @@ func NewUUID() (string, error) {
 	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
 }
 
+//NewRandomBytes returns n bytes from the crypto random source
+func NewRandomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+//NewRandomString returns n alphanumeric characters, falling back to the first letter if the random source fails.
+//
+//Deprecated: the fallback silently produces predictable strings, use NewRandomStringWithAlphabet with
+//AlphanumericAlphabet, which reports random source failures.
 func NewRandomString(n int) string {
 	b := make([]rune, n)
 	for i := range b {
@@ -47,3 +47,14 @@ func TestNewRandomStringNotTheSame(t *testing.T){
 	assert.EqualValues(t, expectedLength, len(resultOne))
 	assert.EqualValues(t, expectedLength, len(resultTwo))
 }
+
+func TestNewRandomBytes(t *testing.T){
+	//act
+	first, err := NewRandomBytes(20)
+	second, _ := NewRandomBytes(20)
+
+	//assert
+	assert.Nil(t, err)
+	assert.EqualValues(t, 20, len(first))
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,202 @@
+package random_utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/lelinu/api_utils/utils/env_utils"
+)
+
+const (
+	DefaultSnowflakeDatacenterBits   = 5
+	DefaultSnowflakeWorkerBits       = 5
+	DefaultSnowflakeSequenceBits     = 12
+	DefaultSnowflakeMaxClockRollback = 10 * time.Millisecond
+	maxSnowflakeNodeAndSequenceBits  = 22
+)
+
+var (
+	// DefaultSnowflakeEpoch start of the snowflake timestamps, 2020-01-01 UTC
+	DefaultSnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ErrClockMovedBackwards = errors.New("random: clock moved backwards, refusing to generate snowflake ids")
+
+	trailingNumber = regexp.MustCompile(`([0-9]+)$`)
+)
+
+//SnowflakeOptions layout and node of a snowflake generator, zero values use the defaults
+type SnowflakeOptions struct {
+	// Epoch start of the timestamps, must never change once ids have been generated
+	Epoch          time.Time
+	DatacenterBits uint8
+	WorkerBits     uint8
+	SequenceBits   uint8
+	DatacenterID   int64
+	WorkerID       int64
+	// WorkerIDEnv optional environment variable holding the worker id, such as the ordinal of a stateful set
+	// pod in HOSTNAME=api-3. It takes precedence over WorkerID when set.
+	WorkerIDEnv string
+	// MaxClockRollback how long the generator waits for the clock to catch up after it moved backwards,
+	// larger rollbacks fail with ErrClockMovedBackwards
+	MaxClockRollback time.Duration
+	// Clock used for the timestamps and to wait for the next millisecond, defaults to date_utils.RealClock
+	Clock date_utils.Clock
+}
+
+//SnowflakeID parts of a snowflake id
+type SnowflakeID struct {
+	Time         time.Time
+	DatacenterID int64
+	WorkerID     int64
+	Sequence     int64
+}
+
+//SnowflakeGenerator generates 64 bit ids, unique across nodes without coordination, laid out from the most
+//significant bit as 0 | milliseconds since epoch | datacenter id | worker id | sequence
+type SnowflakeGenerator struct {
+	mu               sync.Mutex
+	epoch            time.Time
+	datacenterBits   uint8
+	workerBits       uint8
+	sequenceBits     uint8
+	datacenterID     int64
+	workerID         int64
+	maxClockRollback time.Duration
+	clock            date_utils.Clock
+	lastMillis       int64
+	sequence         int64
+}
+
+//NewSnowflakeGenerator constructor
+func NewSnowflakeGenerator(options SnowflakeOptions) (*SnowflakeGenerator, error) {
+	g := &SnowflakeGenerator{
+		epoch:            options.Epoch,
+		datacenterBits:   options.DatacenterBits,
+		workerBits:       options.WorkerBits,
+		sequenceBits:     options.SequenceBits,
+		datacenterID:     options.DatacenterID,
+		workerID:         options.WorkerID,
+		maxClockRollback: options.MaxClockRollback,
+		clock:            options.Clock,
+		lastMillis:       -1,
+	}
+
+	if g.epoch.IsZero() {
+		g.epoch = DefaultSnowflakeEpoch
+	}
+	if g.datacenterBits == 0 && g.workerBits == 0 && g.sequenceBits == 0 {
+		g.datacenterBits = DefaultSnowflakeDatacenterBits
+		g.workerBits = DefaultSnowflakeWorkerBits
+		g.sequenceBits = DefaultSnowflakeSequenceBits
+	}
+	if g.maxClockRollback == 0 {
+		g.maxClockRollback = DefaultSnowflakeMaxClockRollback
+	}
+	if g.clock == nil {
+		g.clock = date_utils.RealClock{}
+	}
+
+	if options.WorkerIDEnv != "" {
+		if value := env_utils.GetEnv(options.WorkerIDEnv, ""); value != "" {
+			workerID, err := parseWorkerID(value)
+			if err != nil {
+				return nil, fmt.Errorf("random: %s: %v", options.WorkerIDEnv, err)
+			}
+			g.workerID = workerID
+		}
+	}
+
+	if g.sequenceBits == 0 {
+		return nil, errors.New("random: snowflake ids need at least one sequence bit")
+	}
+	if int(g.datacenterBits)+int(g.workerBits)+int(g.sequenceBits) > maxSnowflakeNodeAndSequenceBits {
+		return nil, fmt.Errorf("random: datacenter, worker and sequence bits must not exceed %d", maxSnowflakeNodeAndSequenceBits)
+	}
+	if g.datacenterID < 0 || g.datacenterID > maxForBits(g.datacenterBits) {
+		return nil, fmt.Errorf("random: datacenter id must be between 0 and %d", maxForBits(g.datacenterBits))
+	}
+	if g.workerID < 0 || g.workerID > maxForBits(g.workerBits) {
+		return nil, fmt.Errorf("random: worker id must be between 0 and %d", maxForBits(g.workerBits))
+	}
+	if g.clock.Now().Before(g.epoch) {
+		return nil, errors.New("random: snowflake epoch is in the future")
+	}
+
+	return g, nil
+}
+
+//New returns a new id, greater than every id previously returned by the generator
+func (g *SnowflakeGenerator) New() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis := g.millisSinceEpoch()
+	if millis < g.lastMillis {
+		rollback := time.Duration(g.lastMillis-millis) * time.Millisecond
+		if rollback > g.maxClockRollback {
+			return 0, ErrClockMovedBackwards
+		}
+		millis = g.waitUntilAfter(g.lastMillis - 1)
+	}
+
+	if millis == g.lastMillis {
+		g.sequence = (g.sequence + 1) & maxForBits(g.sequenceBits)
+		if g.sequence == 0 {
+			// sequence exhausted for this millisecond
+			millis = g.waitUntilAfter(g.lastMillis)
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMillis = millis
+
+	return millis<<(g.datacenterBits+g.workerBits+g.sequenceBits) |
+		g.datacenterID<<(g.workerBits+g.sequenceBits) |
+		g.workerID<<g.sequenceBits |
+		g.sequence, nil
+}
+
+//Decompose returns the parts of an id produced by a generator with the same options
+func (g *SnowflakeGenerator) Decompose(id int64) SnowflakeID {
+	millis := id >> (g.datacenterBits + g.workerBits + g.sequenceBits)
+	return SnowflakeID{
+		Time:         g.epoch.Add(time.Duration(millis) * time.Millisecond),
+		DatacenterID: (id >> (g.workerBits + g.sequenceBits)) & maxForBits(g.datacenterBits),
+		WorkerID:     (id >> g.sequenceBits) & maxForBits(g.workerBits),
+		Sequence:     id & maxForBits(g.sequenceBits),
+	}
+}
+
+//millisSinceEpoch returns the current time in milliseconds since the epoch
+func (g *SnowflakeGenerator) millisSinceEpoch() int64 {
+	return g.clock.Now().Sub(g.epoch).Milliseconds()
+}
+
+//waitUntilAfter sleeps until the clock is past the given millisecond
+func (g *SnowflakeGenerator) waitUntilAfter(lastMillis int64) int64 {
+	millis := g.millisSinceEpoch()
+	for millis <= lastMillis {
+		g.clock.Sleep(time.Duration(lastMillis-millis+1) * time.Millisecond)
+		millis = g.millisSinceEpoch()
+	}
+	return millis
+}
+
+//maxForBits returns the largest value held by the number of bits
+func maxForBits(bits uint8) int64 {
+	return int64(1)<<bits - 1
+}
+
+//parseWorkerID parses a number or the trailing number of a host name such as api-3
+func parseWorkerID(value string) (int64, error) {
+	match := trailingNumber.FindString(value)
+	if match == "" {
+		return 0, fmt.Errorf("no worker id in %q", value)
+	}
+	return strconv.ParseInt(match, 10, 64)
+}
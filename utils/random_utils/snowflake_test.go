@@ -0,0 +1,168 @@
+package random_utils
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var snowflakeTestStart = time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+
+func newTestSnowflakeGenerator(t *testing.T, options SnowflakeOptions) (*SnowflakeGenerator, *date_utils.FakeClock) {
+	clock := date_utils.NewFakeClock(snowflakeTestStart)
+	options.Clock = clock
+	generator, err := NewSnowflakeGenerator(options)
+	assert.Nil(t, err)
+	return generator, clock
+}
+
+//newWhileSleeping calls New in another goroutine, advancing the clock by d once the generator sleeps
+func newWhileSleeping(generator *SnowflakeGenerator, clock *date_utils.FakeClock, d time.Duration) (int64, error) {
+	type result struct {
+		id  int64
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		id, err := generator.New()
+		done <- result{id, err}
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(d)
+	r := <-done
+	return r.id, r.err
+}
+
+func TestSnowflakeNewAndDecompose(t *testing.T) {
+	//arrange
+	generator, clock := newTestSnowflakeGenerator(t, SnowflakeOptions{DatacenterID: 3, WorkerID: 17})
+
+	//act
+	first, err := generator.New()
+	second, _ := generator.New()
+	parts := generator.Decompose(second)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, second > first)
+	assert.Equal(t, clock.Now(), parts.Time)
+	assert.EqualValues(t, 3, parts.DatacenterID)
+	assert.EqualValues(t, 17, parts.WorkerID)
+	assert.EqualValues(t, 1, parts.Sequence)
+}
+
+func TestSnowflakeSequenceExhaustionWaitsForNextMillisecond(t *testing.T) {
+	//arrange
+	generator, clock := newTestSnowflakeGenerator(t, SnowflakeOptions{WorkerBits: 2, SequenceBits: 2})
+	start := clock.Now()
+
+	//act
+	ids := make([]int64, 0, 5)
+	for i := 0; i < 4; i++ {
+		id, err := generator.New()
+		assert.Nil(t, err)
+		ids = append(ids, id)
+	}
+	id, err := newWhileSleeping(generator, clock, time.Millisecond)
+	ids = append(ids, id)
+	last := generator.Decompose(ids[4])
+
+	//assert
+	assert.Nil(t, err)
+	for i := 1; i < len(ids); i++ {
+		assert.True(t, ids[i] > ids[i-1])
+	}
+	assert.Equal(t, start.Add(time.Millisecond), last.Time)
+	assert.EqualValues(t, 0, last.Sequence)
+}
+
+func TestSnowflakeSmallClockRollbackWaits(t *testing.T) {
+	//arrange
+	generator, clock := newTestSnowflakeGenerator(t, SnowflakeOptions{})
+	before, _ := generator.New()
+
+	//act
+	clock.Set(clock.Now().Add(-5 * time.Millisecond))
+	after, err := newWhileSleeping(generator, clock, 5*time.Millisecond)
+
+	//assert
+	assert.Nil(t, err)
+	assert.True(t, after > before)
+}
+
+func TestSnowflakeLargeClockRollbackFails(t *testing.T) {
+	//arrange
+	generator, clock := newTestSnowflakeGenerator(t, SnowflakeOptions{})
+	_, _ = generator.New()
+
+	//act
+	clock.Set(clock.Now().Add(-time.Second))
+	_, err := generator.New()
+
+	//assert
+	assert.Equal(t, ErrClockMovedBackwards, err)
+}
+
+func TestSnowflakeConcurrentIDsAreUnique(t *testing.T) {
+	//arrange
+	generator, err := NewSnowflakeGenerator(SnowflakeOptions{})
+	assert.Nil(t, err)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := map[int64]bool{}
+
+	//act
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				id, err := generator.New()
+				assert.Nil(t, err)
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	//assert
+	assert.EqualValues(t, 8000, len(seen))
+}
+
+func TestSnowflakeWorkerIDFromEnv(t *testing.T) {
+	//arrange
+	os.Setenv("SNOWFLAKE_TEST_HOSTNAME", "api-7")
+	defer os.Unsetenv("SNOWFLAKE_TEST_HOSTNAME")
+
+	//act
+	generator, _ := newTestSnowflakeGenerator(t, SnowflakeOptions{WorkerID: 1, WorkerIDEnv: "SNOWFLAKE_TEST_HOSTNAME"})
+	id, _ := generator.New()
+
+	//assert
+	assert.EqualValues(t, 7, generator.Decompose(id).WorkerID)
+}
+
+func TestSnowflakeInvalidOptions(t *testing.T) {
+	//arrange
+	os.Setenv("SNOWFLAKE_TEST_HOSTNAME", "api")
+	defer os.Unsetenv("SNOWFLAKE_TEST_HOSTNAME")
+
+	//act
+	_, workerErr := NewSnowflakeGenerator(SnowflakeOptions{WorkerID: 32})
+	_, bitsErr := NewSnowflakeGenerator(SnowflakeOptions{WorkerBits: 10, DatacenterBits: 10, SequenceBits: 10})
+	_, envErr := NewSnowflakeGenerator(SnowflakeOptions{WorkerIDEnv: "SNOWFLAKE_TEST_HOSTNAME"})
+	_, epochErr := NewSnowflakeGenerator(SnowflakeOptions{Epoch: time.Now().Add(time.Hour)})
+
+	//assert
+	assert.NotNil(t, workerErr)
+	assert.NotNil(t, bitsErr)
+	assert.NotNil(t, envErr)
+	assert.NotNil(t, epochErr)
+}
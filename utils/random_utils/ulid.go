@@ -0,0 +1,195 @@
+package random_utils
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+)
+
+const (
+	ulidLength = 26
+	// Crockford's base32 alphabet, without I, L, O and U
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var (
+	ErrInvalidULID  = errors.New("random: invalid ulid")
+	ErrULIDOverflow = errors.New("random: ulid random component overflowed within the same millisecond")
+
+	defaultULIDGenerator = NewULIDGenerator(nil, nil)
+	crockfordValues      = crockfordDecodingTable()
+)
+
+//ULID universally unique lexicographically sortable identifier, 48 bit millisecond timestamp and 80 random bits
+//encoded as 26 Crockford base32 characters, stored as its string by Value and scanned from a string or 16 raw bytes
+type ULID [16]byte
+
+//NewULID returns a new ulid string
+func NewULID() (string, error) {
+	ulid, err := defaultULIDGenerator.New()
+	if err != nil {
+		return "", err
+	}
+	return ulid.String(), nil
+}
+
+//ParseULID parses a ulid, case insensitive
+func ParseULID(value string) (ULID, error) {
+	var ulid ULID
+	if len(value) != ulidLength {
+		return ulid, ErrInvalidULID
+	}
+
+	var hi, lo uint64
+	for i := 0; i < ulidLength; i++ {
+		v := crockfordValues[value[i]]
+		if v == 0xff {
+			return ulid, ErrInvalidULID
+		}
+		// the first character only holds 3 bits, 26 characters hold 130 bits
+		if i == 0 && v > 7 {
+			return ulid, ErrInvalidULID
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	for i := 0; i < 8; i++ {
+		ulid[i] = byte(hi >> (56 - 8*i))
+		ulid[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return ulid, nil
+}
+
+//String returns the 26 character upper case form
+func (u ULID) String() string {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(u[i])
+		lo = lo<<8 | uint64(u[8+i])
+	}
+
+	out := make([]byte, ulidLength)
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+//Time returns the creation time, with millisecond precision
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(int64(readUint48(u[0:6]))).UTC()
+}
+
+//MarshalText encodes the ulid as its string, also used for json
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+//UnmarshalText decodes the ulid string
+func (u *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+//Value implements driver.Valuer
+func (u ULID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+//Scan implements sql.Scanner, accepting the string or 16 raw bytes of a BINARY(16) column
+func (u *ULID) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case string:
+		return u.UnmarshalText([]byte(value))
+	case []byte:
+		if len(value) == len(u) {
+			copy(u[:], value)
+			return nil
+		}
+		return u.UnmarshalText(value)
+	}
+	return fmt.Errorf("random: cannot scan %T into ulid", src)
+}
+
+//ULIDGenerator generates monotonic ulids: within the same millisecond, or when the clock goes backwards,
+//the random component of the previous ulid is incremented instead of drawn again
+type ULIDGenerator struct {
+	mu         sync.Mutex
+	clock      date_utils.Clock
+	entropy    io.Reader
+	lastMillis uint64
+	last       ULID
+}
+
+//NewULIDGenerator constructor, clock and entropy default to date_utils.RealClock and crypto/rand when nil
+func NewULIDGenerator(clock date_utils.Clock, entropy io.Reader) *ULIDGenerator {
+	if clock == nil {
+		clock = date_utils.RealClock{}
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	return &ULIDGenerator{
+		clock:   clock,
+		entropy: entropy,
+	}
+}
+
+//New returns a new ulid, greater than every ulid previously returned by the generator
+func (g *ULIDGenerator) New() (ULID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis := uint64(g.clock.Now().UnixMilli())
+	if millis > g.lastMillis || g.lastMillis == 0 {
+		var ulid ULID
+		writeUint48(ulid[0:6], millis)
+		if _, err := io.ReadFull(g.entropy, ulid[6:]); err != nil {
+			return ulid, err
+		}
+		g.lastMillis = millis
+		g.last = ulid
+		return ulid, nil
+	}
+
+	ulid := g.last
+	for i := len(ulid) - 1; i >= 6; i-- {
+		ulid[i]++
+		if ulid[i] != 0 {
+			g.last = ulid
+			return ulid, nil
+		}
+	}
+	return ULID{}, ErrULIDOverflow
+}
+
+//crockfordDecodingTable maps characters to their value, 0xff for invalid ones, accepting lower case
+//and the I, L and O aliases
+func crockfordDecodingTable() [256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = 0xff
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		table[crockfordAlphabet[i]] = byte(i)
+		table[strings.ToLower(crockfordAlphabet[i : i+1])[0]] = byte(i)
+	}
+	table['I'], table['i'], table['L'], table['l'] = 1, 1, 1, 1
+	table['O'], table['o'] = 0, 0
+	return table
+}
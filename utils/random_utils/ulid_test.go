@@ -0,0 +1,106 @@
+package random_utils
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewULID(t *testing.T) {
+	//act
+	value, err := NewULID()
+	_, parseErr := ParseULID(value)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, parseErr)
+	assert.EqualValues(t, 26, len(value))
+}
+
+func TestULIDEncodingAndTime(t *testing.T) {
+	//arrange
+	now := time.Date(2021, 6, 1, 10, 30, 0, 123000000, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	generator := NewULIDGenerator(clock, fixedReader(0))
+
+	//act
+	ulid, err := generator.New()
+	parsed, parseErr := ParseULID(strings.ToLower(ulid.String()))
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, parseErr)
+	assert.Equal(t, "01F73HYZ5V0000000000000000", ulid.String())
+	assert.Equal(t, now, ulid.Time())
+	assert.Equal(t, ulid, parsed)
+}
+
+func TestULIDMonotonicWithinMillisecond(t *testing.T) {
+	//arrange
+	now := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	generator := NewULIDGenerator(clock, nil)
+	values := make([]string, 0, 1000)
+
+	//act
+	for i := 0; i < 1000; i++ {
+		ulid, err := generator.New()
+		assert.Nil(t, err)
+		values = append(values, ulid.String())
+	}
+	clock.Set(now.Add(-time.Second))
+	afterRollback, _ := generator.New()
+
+	//assert
+	assert.True(t, sort.StringsAreSorted(values))
+	assert.True(t, afterRollback.String() > values[len(values)-1])
+}
+
+func TestULIDOverflow(t *testing.T) {
+	//arrange
+	now := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	generator := NewULIDGenerator(clock, fixedReader(0xff))
+
+	//act
+	_, err := generator.New()
+	_, overflowErr := generator.New()
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, ErrULIDOverflow, overflowErr)
+}
+
+func TestParseULIDInvalid(t *testing.T) {
+	//act
+	_, lengthErr := ParseULID("01F73EY6MV")
+	_, charErr := ParseULID("01F73EY6MV000000000000000U")
+	_, overflowErr := ParseULID("81F73EY6MV0000000000000000")
+
+	//assert
+	assert.Equal(t, ErrInvalidULID, lengthErr)
+	assert.Equal(t, ErrInvalidULID, charErr)
+	assert.Equal(t, ErrInvalidULID, overflowErr)
+}
+
+func TestULIDScanAndValue(t *testing.T) {
+	//arrange
+	ulid, _ := NewULIDGenerator(nil, nil).New()
+	var fromString, fromBytes ULID
+
+	//act
+	value, valueErr := ulid.Value()
+	stringErr := fromString.Scan(value)
+	bytesErr := fromBytes.Scan(ulid[:])
+
+	//assert
+	assert.Nil(t, valueErr)
+	assert.Nil(t, stringErr)
+	assert.Nil(t, bytesErr)
+	assert.Equal(t, ulid, fromString)
+	assert.Equal(t, ulid, fromBytes)
+}
@@ -0,0 +1,173 @@
+package random_utils
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+)
+
+var (
+	ErrInvalidUUID = errors.New("random: invalid uuid")
+
+	defaultUUIDv7Generator = NewUUIDv7Generator(nil, nil)
+)
+
+//UUID RFC 9562 uuid, stored as its canonical string by Value and scanned from a string or 16 raw bytes
+type UUID [16]byte
+
+//NewUUIDv7 returns a new time ordered version 7 uuid string
+func NewUUIDv7() (string, error) {
+	uuid, err := defaultUUIDv7Generator.New()
+	if err != nil {
+		return "", err
+	}
+	return uuid.String(), nil
+}
+
+//ParseUUID parses a uuid in its canonical 8-4-4-4-12 form
+func ParseUUID(value string) (UUID, error) {
+	var uuid UUID
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return uuid, ErrInvalidUUID
+	}
+
+	digits := value[0:8] + value[9:13] + value[14:18] + value[19:23] + value[24:36]
+	if _, err := hex.Decode(uuid[:], []byte(digits)); err != nil {
+		return uuid, ErrInvalidUUID
+	}
+	return uuid, nil
+}
+
+//String returns the canonical lower case form
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+//Version returns the uuid version
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+//Time returns the creation time of a version 7 uuid, with millisecond precision
+func (u UUID) Time() (time.Time, error) {
+	if u.Version() != 7 {
+		return time.Time{}, fmt.Errorf("random: uuid version %d has no timestamp", u.Version())
+	}
+	return time.UnixMilli(int64(readUint48(u[0:6]))).UTC(), nil
+}
+
+//MarshalText encodes the uuid as its canonical string, also used for json
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+//UnmarshalText decodes the canonical string
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+//Value implements driver.Valuer
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+//Scan implements sql.Scanner, accepting the canonical string or 16 raw bytes of a BINARY(16) column
+func (u *UUID) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case string:
+		return u.UnmarshalText([]byte(value))
+	case []byte:
+		if len(value) == len(u) {
+			copy(u[:], value)
+			return nil
+		}
+		return u.UnmarshalText(value)
+	}
+	return fmt.Errorf("random: cannot scan %T into uuid", src)
+}
+
+//UUIDv7Generator generates version 7 uuids, which start with a millisecond timestamp so that they sort by
+//creation time. Uuids generated within the same millisecond use a 12 bit counter, seeded randomly, so that
+//they remain ordered, and the clock going backwards never breaks the ordering.
+type UUIDv7Generator struct {
+	mu         sync.Mutex
+	clock      date_utils.Clock
+	entropy    io.Reader
+	lastMillis uint64
+	counter    uint16
+}
+
+//NewUUIDv7Generator constructor, clock and entropy default to date_utils.RealClock and crypto/rand when nil
+func NewUUIDv7Generator(clock date_utils.Clock, entropy io.Reader) *UUIDv7Generator {
+	if clock == nil {
+		clock = date_utils.RealClock{}
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	return &UUIDv7Generator{
+		clock:   clock,
+		entropy: entropy,
+	}
+}
+
+//New returns a new uuid, greater than every uuid previously returned by the generator
+func (g *UUIDv7Generator) New() (UUID, error) {
+	var uuid UUID
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, err := io.ReadFull(g.entropy, uuid[6:]); err != nil {
+		return uuid, err
+	}
+
+	millis := uint64(g.clock.Now().UnixMilli())
+	if millis > g.lastMillis {
+		// seed the counter with 11 random bits, leaving room to increment it within the millisecond
+		g.lastMillis = millis
+		g.counter = binary.BigEndian.Uint16(uuid[6:8]) & 0x07ff
+	} else {
+		// same millisecond or clock rollback, keep the last timestamp and increment the counter
+		g.counter++
+		if g.counter > 0x0fff {
+			g.lastMillis++
+			g.counter = 0
+		}
+	}
+
+	writeUint48(uuid[0:6], g.lastMillis)
+	binary.BigEndian.PutUint16(uuid[6:8], 0x7000|g.counter)
+	// variant bits; see section 4.1
+	uuid[8] = uuid[8]&^0xc0 | 0x80
+	return uuid, nil
+}
+
+//readUint48 reads a big endian 48 bit integer
+func readUint48(b []byte) uint64 {
+	return uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+}
+
+//writeUint48 writes a big endian 48 bit integer
+func writeUint48(b []byte, v uint64) {
+	b[0] = byte(v >> 40)
+	b[1] = byte(v >> 32)
+	b[2] = byte(v >> 24)
+	b[3] = byte(v >> 16)
+	b[4] = byte(v >> 8)
+	b[5] = byte(v)
+}
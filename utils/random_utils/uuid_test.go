@@ -0,0 +1,134 @@
+package random_utils
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lelinu/api_utils/utils/date_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+//fixedReader entropy source returning the same byte forever
+type fixedReader byte
+
+func (r fixedReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func TestNewUUIDv7(t *testing.T) {
+	//act
+	value, err := NewUUIDv7()
+	uuid, parseErr := ParseUUID(value)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, parseErr)
+	assert.EqualValues(t, 7, uuid.Version())
+	assert.EqualValues(t, 0x80, uuid[8]&0xc0)
+}
+
+func TestUUIDv7TimeExtraction(t *testing.T) {
+	//arrange
+	now := time.Date(2021, 6, 1, 10, 30, 0, 123000000, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	generator := NewUUIDv7Generator(clock, fixedReader(0))
+
+	//act
+	uuid, err := generator.New()
+	createdAt, timeErr := uuid.Time()
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, timeErr)
+	assert.Equal(t, now, createdAt)
+	assert.Equal(t, "0179c71f-7cbb-7000-8000-000000000000", uuid.String())
+}
+
+func TestUUIDv7MonotonicWithinMillisecond(t *testing.T) {
+	//arrange
+	now := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	generator := NewUUIDv7Generator(clock, nil)
+	values := make([]string, 0, 5000)
+
+	//act
+	for i := 0; i < 5000; i++ {
+		uuid, err := generator.New()
+		assert.Nil(t, err)
+		values = append(values, uuid.String())
+	}
+	clock.Set(now.Add(-time.Second))
+	afterRollback, _ := generator.New()
+
+	//assert
+	assert.True(t, sort.StringsAreSorted(values))
+	assert.True(t, afterRollback.String() > values[len(values)-1])
+}
+
+func TestUUIDv4HasNoTime(t *testing.T) {
+	//arrange
+	value, _ := NewUUID()
+	uuid, _ := ParseUUID(value)
+
+	//act
+	_, err := uuid.Time()
+
+	//assert
+	assert.NotNil(t, err)
+}
+
+func TestParseUUIDInvalid(t *testing.T) {
+	//act
+	_, lengthErr := ParseUUID("0179c6ef-1a9b-7000-8000")
+	_, hexErr := ParseUUID("0179c6ef-1a9b-7000-8000-00000000000z")
+	_, dashErr := ParseUUID("0179c6ef1a9b-7000-8000-0000000000000-")
+
+	//assert
+	assert.Equal(t, ErrInvalidUUID, lengthErr)
+	assert.Equal(t, ErrInvalidUUID, hexErr)
+	assert.Equal(t, ErrInvalidUUID, dashErr)
+}
+
+func TestUUIDScanAndValue(t *testing.T) {
+	//arrange
+	uuid, _ := NewUUIDv7Generator(nil, nil).New()
+	var fromString, fromBytes, fromText UUID
+
+	//act
+	value, valueErr := uuid.Value()
+	stringErr := fromString.Scan(value)
+	bytesErr := fromBytes.Scan(uuid[:])
+	textErr := fromText.Scan([]byte(uuid.String()))
+	invalidErr := fromText.Scan(42)
+
+	//assert
+	assert.Nil(t, valueErr)
+	assert.Nil(t, stringErr)
+	assert.Nil(t, bytesErr)
+	assert.Nil(t, textErr)
+	assert.NotNil(t, invalidErr)
+	assert.Equal(t, uuid, fromString)
+	assert.Equal(t, uuid, fromBytes)
+	assert.Equal(t, uuid, fromText)
+}
+
+func TestUUIDJSON(t *testing.T) {
+	//arrange
+	uuid, _ := NewUUIDv7Generator(nil, nil).New()
+	var decoded struct{ ID UUID }
+
+	//act
+	data, err := json.Marshal(struct{ ID UUID }{uuid})
+	decodeErr := json.Unmarshal(data, &decoded)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Nil(t, decodeErr)
+	assert.Equal(t, `{"ID":"`+uuid.String()+`"}`, string(data))
+	assert.Equal(t, uuid, decoded.ID)
+}
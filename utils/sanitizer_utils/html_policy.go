@@ -0,0 +1,167 @@
+package sanitizer_utils
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// elements whose content is dropped together with the element
+	droppedContentElements = map[string]bool{
+		"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+		"noscript": true, "noembed": true, "noframes": true, "template": true,
+		"textarea": true, "title": true, "xmp": true, "plaintext": true, "svg": true, "math": true,
+	}
+
+	voidElements = map[string]bool{
+		"area": true, "br": true, "col": true, "hr": true, "img": true, "wbr": true,
+	}
+
+	urlAttributes = map[string]bool{
+		"href": true, "src": true, "cite": true,
+	}
+
+	strictHTMLPolicy = NewHTMLPolicy()
+)
+
+//HTMLPolicy allowlist of html elements, attributes and url schemes
+type HTMLPolicy struct {
+	elements   map[string]map[string]bool
+	urlSchemes map[string]bool
+}
+
+//NewHTMLPolicy constructor for an empty policy, which strips every element
+func NewHTMLPolicy() *HTMLPolicy {
+	return &HTMLPolicy{
+		elements:   map[string]map[string]bool{},
+		urlSchemes: map[string]bool{"http": true, "https": true, "mailto": true},
+	}
+}
+
+//NewRichTextHTMLPolicy constructor for a policy suited to user generated rich text
+func NewRichTextHTMLPolicy() *HTMLPolicy {
+	p := NewHTMLPolicy()
+	p.AllowElements("p", "br", "hr", "b", "strong", "i", "em", "u", "s", "sub", "sup", "small",
+		"h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "li", "pre", "code", "span",
+		"table", "thead", "tbody", "tr", "th", "td")
+	p.AllowAttributes("blockquote", "cite")
+	p.AllowAttributes("a", "href", "title")
+	p.AllowAttributes("img", "src", "alt", "title", "width", "height")
+	return p
+}
+
+//AllowElements allows the elements without any attribute
+func (p *HTMLPolicy) AllowElements(elements ...string) *HTMLPolicy {
+	for _, element := range elements {
+		element = strings.ToLower(element)
+		if _, ok := p.elements[element]; !ok {
+			p.elements[element] = map[string]bool{}
+		}
+	}
+	return p
+}
+
+//AllowAttributes allows the element together with the given attributes
+func (p *HTMLPolicy) AllowAttributes(element string, attributes ...string) *HTMLPolicy {
+	p.AllowElements(element)
+	element = strings.ToLower(element)
+	for _, attribute := range attributes {
+		p.elements[element][strings.ToLower(attribute)] = true
+	}
+	return p
+}
+
+//AllowURLSchemes replaces the url schemes allowed in href and src attributes, relative urls are always allowed
+func (p *HTMLPolicy) AllowURLSchemes(schemes ...string) *HTMLPolicy {
+	p.urlSchemes = map[string]bool{}
+	for _, scheme := range schemes {
+		p.urlSchemes[strings.ToLower(scheme)] = true
+	}
+	return p
+}
+
+//Sanitize returns the input keeping only allowed elements and attributes, with all text escaped
+func (p *HTMLPolicy) Sanitize(input string) string {
+	var b strings.Builder
+	dropDepth := 0
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		// io.EOF, or a malformed document, ends the output
+		if tokenType == html.ErrorToken {
+			return b.String()
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.TextToken:
+			if dropDepth == 0 {
+				b.WriteString(html.EscapeString(token.Data))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedContentElements[token.Data] {
+				if tokenType == html.StartTagToken {
+					dropDepth++
+				}
+				continue
+			}
+			if dropDepth > 0 {
+				continue
+			}
+			attributes, ok := p.elements[token.Data]
+			if !ok {
+				continue
+			}
+			b.WriteString("<" + token.Data)
+			for _, attribute := range token.Attr {
+				if attribute.Namespace != "" || !attributes[attribute.Key] {
+					continue
+				}
+				if urlAttributes[attribute.Key] && !p.isAllowedURL(attribute.Val) {
+					continue
+				}
+				b.WriteString(" " + attribute.Key + `="` + html.EscapeString(attribute.Val) + `"`)
+			}
+			b.WriteString(">")
+
+		case html.EndTagToken:
+			if droppedContentElements[token.Data] {
+				if dropDepth > 0 {
+					dropDepth--
+				}
+				continue
+			}
+			if dropDepth > 0 || voidElements[token.Data] {
+				continue
+			}
+			if _, ok := p.elements[token.Data]; ok {
+				b.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+//isAllowedURL checks that the url is relative or uses an allowed scheme
+func (p *HTMLPolicy) isAllowedURL(value string) bool {
+	value = strings.TrimSpace(value)
+
+	// browsers ignore embedded control characters, so java\tscript: would still run
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	return p.urlSchemes[strings.ToLower(u.Scheme)]
+}
@@ -0,0 +1,52 @@
+package sanitizer_utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRichTextHTMLPolicySuccessful(t *testing.T) {
+	tests := map[string]string{
+		`<b>bold</b> text`:                                      `<b>bold</b> text`,
+		`<script>alert(1)</script>safe`:                         `safe`,
+		`<img src=x onerror=alert(1)>`:                          `<img src="x">`,
+		`<a href="javascript:alert(1)">x</a>`:                   `<a>x</a>`,
+		`<a href="JaVaScRiPt&#58;alert(1)">x</a>`:               `<a>x</a>`,
+		`<a href="java&#x09;script:alert(1)">x</a>`:             `<a>x</a>`,
+		`<a href="https://example.com" target="_blank">x</a>`:   `<a href="https://example.com">x</a>`,
+		`<a href="/relative?a=1&b=2">x</a>`:                     `<a href="/relative?a=1&amp;b=2">x</a>`,
+		`<div><p>nested</p></div>`:                              `<p>nested</p>`,
+		`<svg><script>alert(1)</script></svg>after`:             `after`,
+		`<!-- comment --><p title="x">p</p>`:                    `<p>p</p>`,
+		`<p>"quoted" &lt;tag&gt;</p>`:                           `<p>&#34;quoted&#34; &lt;tag&gt;</p>`,
+		`<iframe src="https://evil.com"></iframe><i>italic</i>`: `<i>italic</i>`,
+		`<br/>line`: `<br>line`,
+	}
+
+	policy := NewRichTextHTMLPolicy()
+	for input, expected := range tests {
+		// act
+		output := policy.Sanitize(input)
+
+		// assert
+		assert.EqualValues(t, expected, output, input)
+	}
+}
+
+func TestCustomHTMLPolicySuccessful(t *testing.T) {
+	// arrange
+	policy := NewHTMLPolicy().
+		AllowElements("em").
+		AllowAttributes("a", "href").
+		AllowURLSchemes("https")
+	input := `<em>a</em><b>b</b><a href="http://example.com">c</a><a href="https://example.com">d</a>`
+	expected := `<em>a</em>b<a>c</a><a href="https://example.com">d</a>`
+
+	// act
+	output := NewSanitizerWithHTMLPolicy(policy)
+	output.SanitizeHTML(&input)
+
+	// assert
+	assert.EqualValues(t, expected, input)
+}
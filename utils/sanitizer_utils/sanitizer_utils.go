@@ -0,0 +1,135 @@
+package sanitizer_utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//Sanitizer normalizes user input in place, meant to be run on request structs before the validator_utils.Validator
+type Sanitizer struct {
+	htmlPolicy *HTMLPolicy
+}
+
+//NewSanitizer constructor using the rich text html policy for SanitizeHTML
+func NewSanitizer() *Sanitizer {
+	return &Sanitizer{
+		htmlPolicy: NewRichTextHTMLPolicy(),
+	}
+}
+
+//NewSanitizerWithHTMLPolicy constructor using a custom html policy for SanitizeHTML
+func NewSanitizerWithHTMLPolicy(htmlPolicy *HTMLPolicy) *Sanitizer {
+	return &Sanitizer{
+		htmlPolicy: htmlPolicy,
+	}
+}
+
+//Trim method to remove leading and trailing spaces
+func (s *Sanitizer) Trim(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strings.TrimSpace(*value)
+}
+
+//ToLower method to lowercase the value
+func (s *Sanitizer) ToLower(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strings.ToLower(*value)
+}
+
+//ToUpper method to uppercase the value
+func (s *Sanitizer) ToUpper(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strings.ToUpper(*value)
+}
+
+//NormalizeEmail method to trim and lowercase an email address
+func (s *Sanitizer) NormalizeEmail(value *string) {
+	if value == nil {
+		return
+	}
+	s.NormalizeUnicode(value)
+	s.StripControlCharacters(value)
+	*value = strings.ToLower(strings.TrimSpace(*value))
+}
+
+//CollapseWhitespace method to replace any run of whitespace, including new lines, with a single space
+func (s *Sanitizer) CollapseWhitespace(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strings.Join(strings.Fields(*value), " ")
+}
+
+//NormalizeUnicode method to apply unicode NFC normalization, so that equivalent strings compare equal
+func (s *Sanitizer) NormalizeUnicode(value *string) {
+	if value == nil {
+		return
+	}
+	*value = norm.NFC.String(*value)
+}
+
+//StripControlCharacters method to remove control and invisible format characters, keeping new lines and tabs
+func (s *Sanitizer) StripControlCharacters(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, *value)
+}
+
+//Text method to normalize a single line of plain text: NFC normalization, control characters removed,
+//whitespace collapsed and trimmed
+func (s *Sanitizer) Text(value *string) {
+	if value == nil {
+		return
+	}
+	s.NormalizeUnicode(value)
+	s.StripControlCharacters(value)
+	s.CollapseWhitespace(value)
+}
+
+//SanitizeHTML method to keep only the elements and attributes allowed by the sanitizer html policy
+func (s *Sanitizer) SanitizeHTML(value *string) {
+	if value == nil {
+		return
+	}
+	s.NormalizeUnicode(value)
+	*value = s.htmlPolicy.Sanitize(*value)
+}
+
+//StripHTML method to remove every html element, keeping only the escaped text content
+func (s *Sanitizer) StripHTML(value *string) {
+	if value == nil {
+		return
+	}
+	*value = strictHTMLPolicy.Sanitize(*value)
+}
+
+//TrimSlice method to trim every element and drop the ones left empty
+func (s *Sanitizer) TrimSlice(values *[]string) {
+	if values == nil {
+		return
+	}
+	trimmed := (*values)[:0]
+	for _, value := range *values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	*values = trimmed
+}
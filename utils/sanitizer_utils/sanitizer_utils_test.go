@@ -0,0 +1,113 @@
+package sanitizer_utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signUpRequest struct {
+	Name  string
+	Email string
+	Tags  []string
+	Bio   string
+}
+
+func TestSanitizeStructInPlaceSuccessful(t *testing.T) {
+	// arrange
+	request := &signUpRequest{
+		Name:  "  John \t\n  Doe\u0000 ",
+		Email: " John.Doe@Example.COM ",
+		Tags:  []string{" a ", "  ", "b"},
+		Bio:   `<p onclick="x()">Hi <script>alert(1)</script><b>there</b></p>`,
+	}
+
+	// act
+	sanitizer := NewSanitizer()
+	sanitizer.Text(&request.Name)
+	sanitizer.NormalizeEmail(&request.Email)
+	sanitizer.TrimSlice(&request.Tags)
+	sanitizer.SanitizeHTML(&request.Bio)
+
+	// assert
+	assert.EqualValues(t, "John Doe", request.Name)
+	assert.EqualValues(t, "john.doe@example.com", request.Email)
+	assert.EqualValues(t, []string{"a", "b"}, request.Tags)
+	assert.EqualValues(t, "<p>Hi <b>there</b></p>", request.Bio)
+}
+
+func TestNormalizeUnicodeSuccessful(t *testing.T) {
+	// arrange
+	input := "cafe\u0301"
+	expected := "caf\u00e9"
+
+	// act
+	NewSanitizer().NormalizeUnicode(&input)
+
+	// assert
+	assert.EqualValues(t, expected, input)
+}
+
+func TestStripControlCharactersKeepsNewLinesSuccessful(t *testing.T) {
+	// arrange
+	input := "line\u0007 one\u200b\nline two\t"
+	expected := "line one\nline two\t"
+
+	// act
+	NewSanitizer().StripControlCharacters(&input)
+
+	// assert
+	assert.EqualValues(t, expected, input)
+}
+
+func TestCollapseWhitespaceSuccessful(t *testing.T) {
+	// arrange
+	input := "  hello \n\n  world  "
+	expected := "hello world"
+
+	// act
+	NewSanitizer().CollapseWhitespace(&input)
+
+	// assert
+	assert.EqualValues(t, expected, input)
+}
+
+func TestToLowerAndToUpperSuccessful(t *testing.T) {
+	// arrange
+	lower := "HeLLo"
+	upper := "HeLLo"
+
+	// act
+	sanitizer := NewSanitizer()
+	sanitizer.ToLower(&lower)
+	sanitizer.ToUpper(&upper)
+
+	// assert
+	assert.EqualValues(t, "hello", lower)
+	assert.EqualValues(t, "HELLO", upper)
+}
+
+func TestStripHTMLSuccessful(t *testing.T) {
+	// arrange
+	input := `<a href="https://example.com">5 > 4 &amp; <b>bold</b></a><style>p{}</style>`
+	expected := "5 &gt; 4 &amp; bold"
+
+	// act
+	NewSanitizer().StripHTML(&input)
+
+	// assert
+	assert.EqualValues(t, expected, input)
+}
+
+func TestNilValueIsIgnoredSuccessful(t *testing.T) {
+	// arrange
+	sanitizer := NewSanitizer()
+
+	// act / assert
+	assert.NotPanics(t, func() {
+		sanitizer.Trim(nil)
+		sanitizer.Text(nil)
+		sanitizer.SanitizeHTML(nil)
+		sanitizer.TrimSlice(nil)
+	})
+}
@@ -0,0 +1,62 @@
+package upload_utils
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//MaxFileNameLength maximum length of a sanitized file name, extension included
+const MaxFileNameLength = 100
+
+// dashes next to dots are leftovers of removed characters
+var dotDashes = strings.NewReplacer("-.", ".", ".-", ".")
+
+//SanitizeFileName reduces a client supplied file name to a safe one: directories are removed, accents are
+//stripped, characters other than letters, digits, dots, dashes and underscores become dashes, the extension
+//is lower cased and the name is shortened to MaxFileNameLength. A name made only of removed characters becomes
+//file, an empty string is returned when nothing is left.
+func SanitizeFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+
+	var b strings.Builder
+	lastDash := false
+	for _, r := range norm.NFD.String(fileName) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_'):
+			b.WriteRune(r)
+			lastDash = false
+		case !lastDash:
+			b.WriteByte('-')
+			lastDash = true
+		}
+	}
+
+	name, extension := b.String(), ""
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		name, extension = name[:i], strings.ToLower(strings.TrimRight(name[i:], "-"))
+		if extension == "." {
+			extension = ""
+		}
+	}
+	// leading dots would hide the file or walk up directories
+	name = strings.Trim(dotDashes.Replace(name), ".-")
+	if name == "" && extension != "" {
+		name = "file"
+	}
+
+	if max := MaxFileNameLength - len(extension); len(name) > max {
+		if max <= 0 {
+			return ""
+		}
+		name = strings.TrimRight(name[:max], ".-")
+	}
+	if name == "" {
+		return ""
+	}
+	return name + extension
+}
@@ -0,0 +1,180 @@
+package upload_utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lelinu/api_utils/utils/error_utils"
+	"github.com/lelinu/api_utils/utils/mime_utils"
+	"github.com/lelinu/api_utils/utils/random_utils"
+)
+
+//activeMimeTypes types able to run scripts when served, only allowed when listed explicitly
+var activeMimeTypes = map[string]bool{
+	"image/svg+xml": true,
+}
+
+//File upload that passed the policy, ready to be sent with Key as the file name of a cloud storage UploadFile
+type File struct {
+	// Name sanitized file name
+	Name     string
+	Key      string
+	MimeType string
+	Size     int64
+	// Width and Height of images, 0 for other files
+	Width  int
+	Height int
+	Data   []byte
+}
+
+//Policy checks uploads against allowed mime types, a maximum size and maximum image dimensions
+type Policy struct {
+	allowedMimeTypes []string
+	maxSize          int64
+	maxImageWidth    int
+	maxImageHeight   int
+}
+
+//NewPolicy constructor for a policy accepting files up to maxSize bytes, 0 for no limit, of the allowed mime types.
+//Mime types such as image/* allow a whole family, except active content such as image/svg+xml which must be listed
+//explicitly.
+func NewPolicy(maxSize int64, allowedMimeTypes ...string) *Policy {
+	allowed := make([]string, 0, len(allowedMimeTypes))
+	for _, mimeType := range allowedMimeTypes {
+		allowed = append(allowed, strings.ToLower(strings.TrimSpace(mimeType)))
+	}
+	return &Policy{
+		allowedMimeTypes: allowed,
+		maxSize:          maxSize,
+	}
+}
+
+//SetMaxImageDimensions rejects images wider or higher than the limits, 0 for no limit. Images whose dimensions
+//cannot be read, such as svg or webp, are then rejected as only gif, jpeg and png are decoded.
+func (p *Policy) SetMaxImageDimensions(width int, height int) *Policy {
+	p.maxImageWidth = width
+	p.maxImageHeight = height
+	return p
+}
+
+//Check runs the policy on the file content, returning a 413 error when it is too large and a 415 error when
+//its type is not allowed or its content does not match its extension
+func (p *Policy) Check(fileName string, data []byte) (*File, *error_utils.ApiError) {
+	if p.maxSize > 0 && int64(len(data)) > p.maxSize {
+		return nil, error_utils.NewPayloadTooLargeError(fmt.Sprintf("file exceeds the maximum size of %d bytes", p.maxSize))
+	}
+
+	name := SanitizeFileName(fileName)
+	if name == "" {
+		return nil, error_utils.NewBadRequestError("invalid file name")
+	}
+
+	mimeType := mime_utils.GetMimeType(name)
+	if !p.isAllowed(mimeType) {
+		return nil, error_utils.NewUnsupportedMediaTypeError(fmt.Sprintf("file type %s is not allowed", mimeType))
+	}
+
+	check := mime_utils.CheckExtension(name, data)
+	if check.Mismatch && check.Confidence >= mime_utils.ConfidenceMedium {
+		return nil, error_utils.NewUnsupportedMediaTypeError("file content does not match its extension")
+	}
+	// low confidence detections, such as a csv starting with MZ, are not trusted to be executables
+	if check.Detection != nil && check.Detection.Executable && check.Detection.Confidence > mime_utils.ConfidenceLow &&
+		!p.isAllowed(check.Detection.MediaType) {
+		return nil, error_utils.NewUnsupportedMediaTypeError("executable files are not allowed")
+	}
+
+	file := &File{
+		Name:     name,
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+		Data:     data,
+	}
+
+	if strings.HasPrefix(mimeType, "image/") {
+		if err := p.checkImage(file); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := StorageKey(name)
+	if err != nil {
+		return nil, error_utils.NewInternalServerError("unable to generate the storage key")
+	}
+	file.Key = key
+	return file, nil
+}
+
+//CheckReader runs the policy on a stream, reading at most one byte past the maximum size before rejecting it
+func (p *Policy) CheckReader(fileName string, r io.Reader) (*File, *error_utils.ApiError) {
+	if p.maxSize > 0 {
+		r = io.LimitReader(r, p.maxSize+1)
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, error_utils.NewInternalServerError("unable to read the file")
+	}
+	return p.Check(fileName, data)
+}
+
+//isAllowed checks the mime type against the allowed ones, every type being allowed when none is configured.
+//Wildcards do not match active content.
+func (p *Policy) isAllowed(mimeType string) bool {
+	if len(p.allowedMimeTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.allowedMimeTypes {
+		if allowed == mimeType {
+			return true
+		}
+		if activeMimeTypes[mimeType] {
+			continue
+		}
+		if allowed == "*/*" || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+//checkImage reads the image dimensions into the file and enforces the maximum ones
+func (p *Policy) checkImage(file *File) *error_utils.ApiError {
+	config, _, err := image.DecodeConfig(bytes.NewReader(file.Data))
+	if err != nil {
+		if p.maxImageWidth > 0 || p.maxImageHeight > 0 {
+			return error_utils.NewUnsupportedMediaTypeError("unable to read the image dimensions")
+		}
+		return nil
+	}
+
+	file.Width = config.Width
+	file.Height = config.Height
+	if (p.maxImageWidth > 0 && config.Width > p.maxImageWidth) || (p.maxImageHeight > 0 && config.Height > p.maxImageHeight) {
+		return error_utils.NewPayloadTooLargeError(fmt.Sprintf("image exceeds the maximum dimensions of %dx%d",
+			p.maxImageWidth, p.maxImageHeight))
+	}
+	return nil
+}
+
+//StorageKey returns a collision free key made of a random time ordered prefix and the sanitized file name
+func StorageKey(fileName string) (string, error) {
+	name := SanitizeFileName(fileName)
+	if name == "" {
+		name = "file"
+	}
+
+	prefix, err := random_utils.NewUUIDv7()
+	if err != nil {
+		return "", err
+	}
+	return prefix + "-" + name, nil
+}
@@ -0,0 +1,183 @@
+package upload_utils
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pngImage(width int, height int) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
+
+func TestCheckImageSuccessful(t *testing.T) {
+	//arrange
+	policy := NewPolicy(1<<20, "image/*").SetMaxImageDimensions(100, 100)
+	data := pngImage(64, 32)
+
+	//act
+	file, err := policy.Check("../My Holiday Photo.PNG", data)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "My-Holiday-Photo.png", file.Name)
+	assert.Equal(t, "image/png", file.MimeType)
+	assert.Equal(t, int64(len(data)), file.Size)
+	assert.Equal(t, 64, file.Width)
+	assert.Equal(t, 32, file.Height)
+	assert.True(t, strings.HasSuffix(file.Key, "-My-Holiday-Photo.png"))
+	assert.Len(t, file.Key, 36+1+len(file.Name))
+}
+
+func TestCheckTooLarge(t *testing.T) {
+	//arrange
+	policy := NewPolicy(10, "text/plain")
+
+	//act
+	_, err := policy.Check("notes.txt", []byte("more than ten bytes"))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.HttpStatusCode)
+}
+
+func TestCheckImageDimensionsTooLarge(t *testing.T) {
+	//arrange
+	policy := NewPolicy(0, "image/png").SetMaxImageDimensions(100, 100)
+
+	//act
+	_, err := policy.Check("wide.png", pngImage(101, 10))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.HttpStatusCode)
+}
+
+func TestCheckMimeTypeNotAllowed(t *testing.T) {
+	//arrange
+	policy := NewPolicy(0, "image/*")
+
+	//act
+	_, err := policy.Check("report.pdf", []byte("%PDF-1.7\n"))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.HttpStatusCode)
+}
+
+func TestCheckDisguisedExecutable(t *testing.T) {
+	//arrange
+	policy := NewPolicy(0, "image/*")
+	// DOS header whose e_lfanew, at 0x3C, points to the PE signature right after it
+	data := append([]byte("MZ"), make([]byte, 0x3A)...)
+	data = append(data, 0x40, 0x00, 0x00, 0x00)
+	data = append(data, "PE\x00\x00"...)
+
+	//act
+	_, err := policy.Check("photo.jpg", data)
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.HttpStatusCode)
+}
+
+func TestCheckTextStartingWithMZ(t *testing.T) {
+	//arrange
+	policy := NewPolicy(0, "text/csv")
+	data := []byte("MZ,Mozambique\nZA,South Africa\n")
+
+	//act
+	file, err := policy.Check("countries.csv", data)
+
+	//assert
+	assert.Nil(t, err)
+	assert.Equal(t, "text/csv", file.MimeType)
+}
+
+func TestCheckSvgNeedsExplicitMimeType(t *testing.T) {
+	//arrange
+	wildcard := NewPolicy(0, "image/*")
+	everything := NewPolicy(0, "*/*")
+	explicit := NewPolicy(0, "image/*", "image/svg+xml")
+	data := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+
+	//act
+	_, wildcardErr := wildcard.Check("logo.svg", data)
+	_, everythingErr := everything.Check("logo.svg", data)
+	file, explicitErr := explicit.Check("logo.svg", data)
+
+	//assert
+	assert.NotNil(t, wildcardErr)
+	assert.Equal(t, http.StatusUnsupportedMediaType, wildcardErr.HttpStatusCode)
+	assert.NotNil(t, everythingErr)
+	assert.Nil(t, explicitErr)
+	assert.Equal(t, "image/svg+xml", file.MimeType)
+}
+
+func TestCheckUnreadableImageDimensions(t *testing.T) {
+	//arrange
+	policy := NewPolicy(0, "image/png").SetMaxImageDimensions(100, 100)
+	data := append([]byte("\x89PNG\r\n\x1a\n"), []byte("truncated")...)
+
+	//act
+	_, err := policy.Check("broken.png", data)
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.HttpStatusCode)
+}
+
+func TestCheckInvalidFileName(t *testing.T) {
+	//act
+	_, err := NewPolicy(0).Check("../..", []byte("data"))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.HttpStatusCode)
+}
+
+func TestCheckReaderStopsAfterMaxSize(t *testing.T) {
+	//arrange
+	policy := NewPolicy(100, "text/plain")
+	reader := strings.NewReader(strings.Repeat("a", 1000))
+
+	//act
+	_, err := policy.CheckReader("notes.txt", reader)
+	_, okErr := NewPolicy(100, "text/plain").CheckReader("notes.txt", strings.NewReader("hello"))
+
+	//assert
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.HttpStatusCode)
+	assert.Equal(t, 1000-101, reader.Len())
+	assert.Nil(t, okErr)
+}
+
+func TestSanitizeFileNameSuccessful(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":                      "report.pdf",
+		"C:\\Users\\me\\Résumé.DOCX":      "Resume.docx",
+		"../../etc/passwd":                "passwd",
+		".htaccess":                       "htaccess",
+		"my  file (1).tar.gz":             "my-file-1.tar.gz",
+		"<script>.js":                     "script.js",
+		"日本語.txt":                         "file.txt",
+		"..":                              "",
+		"archive.zip (copy)":              "archive.zip-copy",
+		strings.Repeat("a", 200) + ".png": strings.Repeat("a", MaxFileNameLength-4) + ".png",
+	}
+
+	for input, expected := range tests {
+		//act
+		output := SanitizeFileName(input)
+
+		//assert
+		assert.Equal(t, expected, output, input)
+	}
+}
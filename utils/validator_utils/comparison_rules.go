@@ -0,0 +1,118 @@
+package validator_utils
+
+import (
+	"fmt"
+)
+
+//Ordered constraint for any type that supports the < <= >= > operators, including time.Duration and the uint types
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
+
+//Min function to check whether value is greater or equal to min
+func Min[T Ordered](v *Validator, propertyName string, value, min T) bool {
+	if v.Err != nil {
+		return false
+	}
+	if value < min {
+		v.Err = fmt.Errorf("%s - Value must be greater or equal to %v", propertyName, min)
+		return false
+	}
+	return true
+}
+
+//Max function to check whether value is less or equal to max
+func Max[T Ordered](v *Validator, propertyName string, value, max T) bool {
+	if v.Err != nil {
+		return false
+	}
+	if value > max {
+		v.Err = fmt.Errorf("%s - Value must be less or equal to %v", propertyName, max)
+		return false
+	}
+	return true
+}
+
+//Between function to check whether value is between min and max, both inclusive
+func Between[T Ordered](v *Validator, propertyName string, value, min, max T) bool {
+	if v.Err != nil {
+		return false
+	}
+	if max < min {
+		v.Err = fmt.Errorf("%s - max should be greater or equal to min", propertyName)
+		return false
+	}
+	if value < min || value > max {
+		v.Err = fmt.Errorf("%s - Value must be between %v and %v", propertyName, min, max)
+		return false
+	}
+	return true
+}
+
+//GreaterThan function to check whether value is strictly greater than low
+func GreaterThan[T Ordered](v *Validator, propertyName string, value, low T) bool {
+	if v.Err != nil {
+		return false
+	}
+	if value <= low {
+		v.Err = fmt.Errorf("%s - Value must be greater than %v", propertyName, low)
+		return false
+	}
+	return true
+}
+
+//LessThan function to check whether value is strictly less than high
+func LessThan[T Ordered](v *Validator, propertyName string, value, high T) bool {
+	if v.Err != nil {
+		return false
+	}
+	if value >= high {
+		v.Err = fmt.Errorf("%s - Value must be less than %v", propertyName, high)
+		return false
+	}
+	return true
+}
+
+//MinItems function to check whether a collection has at least min elements
+func MinItems[T any](v *Validator, propertyName string, values []T, min int) bool {
+	if v.Err != nil {
+		return false
+	}
+	if len(values) < min {
+		v.Err = fmt.Errorf("%s - Value must contain at least %d items", propertyName, min)
+		return false
+	}
+	return true
+}
+
+//MaxItems function to check whether a collection has at most max elements
+func MaxItems[T any](v *Validator, propertyName string, values []T, max int) bool {
+	if v.Err != nil {
+		return false
+	}
+	if len(values) > max {
+		v.Err = fmt.Errorf("%s - Value must contain at most %d items", propertyName, max)
+		return false
+	}
+	return true
+}
+
+//UniqueItems function to check whether a collection has no duplicate elements
+func UniqueItems[T comparable](v *Validator, propertyName string, values []T) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	seen := make(map[T]struct{}, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			v.Err = fmt.Errorf("%s - Value must not contain duplicate items, %v is repeated", propertyName, value)
+			return false
+		}
+		seen[value] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package validator_utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinInvalidSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must be greater or equal to 5"
+
+	// act
+	validator := NewValidator()
+	valid := Min(validator, propName, uint8(4), 5)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestMinValidSuccessful(t *testing.T) {
+	// act
+	validator := NewValidator()
+	valid := Min(validator, "PropName", 5, 5)
+
+	// assert
+	assert.EqualValues(t, true, valid)
+	assert.Nil(t, validator.Err)
+}
+
+func TestMaxInvalidDurationSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must be less or equal to 1m0s"
+
+	// act
+	validator := NewValidator()
+	valid := Max(validator, propName, 2*time.Minute, time.Minute)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestBetweenSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must be between 1.5 and 2.5"
+
+	// act
+	validator := NewValidator()
+	valid := Between(validator, propName, 2.0, 1.5, 2.5)
+	assert.EqualValues(t, true, valid)
+	valid = Between(validator, propName, 3.0, 1.5, 2.5)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestBetweenInvalidBoundsSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - max should be greater or equal to min"
+
+	// act
+	validator := NewValidator()
+	valid := Between(validator, propName, 1, 10, 5)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestGreaterThanSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must be greater than 10"
+
+	// act
+	validator := NewValidator()
+	valid := GreaterThan(validator, propName, uint64(10), 10)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestLessThanSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must be less than b"
+
+	// act
+	validator := NewValidator()
+	valid := LessThan(validator, propName, "a", "b")
+	assert.EqualValues(t, true, valid)
+	valid = LessThan(validator, propName, "b", "b")
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestMinItemsSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must contain at least 2 items"
+
+	// act
+	validator := NewValidator()
+	valid := MinItems(validator, propName, []int64{1}, 2)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestMaxItemsSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must contain at most 1 items"
+
+	// act
+	validator := NewValidator()
+	valid := MaxItems(validator, propName, []string{"a", "b"}, 1)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestUniqueItemsSuccessful(t *testing.T) {
+	// arrange
+	propName := "PropName"
+	expectedErr := "PropName - Value must not contain duplicate items, b is repeated"
+
+	// act
+	validator := NewValidator()
+	valid := UniqueItems(validator, propName, []string{"a", "b"})
+	assert.EqualValues(t, true, valid)
+	valid = UniqueItems(validator, propName, []string{"a", "b", "b"})
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestComparisonRulesStopOnPreviousErrorSuccessful(t *testing.T) {
+	// arrange
+	validator := NewValidator()
+	validator.IsNotEmpty("First", "")
+
+	// act
+	valid := Min(validator, "Second", 10, 1)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, "First - Value must not be empty", validator.Err.Error())
+}
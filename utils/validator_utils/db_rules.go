@@ -0,0 +1,119 @@
+package validator_utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+//queryRowContexter implemented by both *sql.DB and *sql.Tx
+type queryRowContexter interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+//IsUnique method to check that no row in table has column equal to value, such as "email must not already exist".
+//table and column are quoted but not escaped, they must never come from user input
+func (v *Validator) IsUnique(ctx context.Context, db *gorm.DB, propertyName string, table string, column string, value interface{}) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	exists, err := rowExists(ctx, db, table, column, value, "", nil)
+	if err != nil {
+		v.Err = fmt.Errorf("%s - Value could not be checked for uniqueness: %v", propertyName, err)
+		return false
+	}
+
+	if exists {
+		v.Err = fmt.Errorf("%s - Value already exists", propertyName)
+		return false
+	}
+	return true
+}
+
+//IsUniqueExcluding method to check that no row other than the one identified by idColumn = id has column equal to value,
+//used when updating an existing record. table, column and idColumn are quoted but not escaped, they must never come
+//from user input
+func (v *Validator) IsUniqueExcluding(ctx context.Context, db *gorm.DB, propertyName string, table string, column string, value interface{},
+	idColumn string, id interface{}) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	exists, err := rowExists(ctx, db, table, column, value, idColumn, id)
+	if err != nil {
+		v.Err = fmt.Errorf("%s - Value could not be checked for uniqueness: %v", propertyName, err)
+		return false
+	}
+
+	if exists {
+		v.Err = fmt.Errorf("%s - Value already exists", propertyName)
+		return false
+	}
+	return true
+}
+
+//Exists method to check that a row in table has column equal to value, such as "category_id must reference an existing row".
+//table and column are quoted but not escaped, they must never come from user input
+func (v *Validator) Exists(ctx context.Context, db *gorm.DB, propertyName string, table string, column string, value interface{}) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	exists, err := rowExists(ctx, db, table, column, value, "", nil)
+	if err != nil {
+		v.Err = fmt.Errorf("%s - Value could not be checked for existence: %v", propertyName, err)
+		return false
+	}
+
+	if !exists {
+		v.Err = fmt.Errorf("%s - Value does not exist", propertyName)
+		return false
+	}
+	return true
+}
+
+//rowExists checks whether a row matches column = value, optionally excluding the row where idColumn = id.
+//The dialect quotes table, column and idColumn but does not escape quotes embedded in them, so they must never come
+//from user input
+func rowExists(ctx context.Context, db *gorm.DB, table string, column string, value interface{}, idColumn string, id interface{}) (bool, error) {
+	if db == nil {
+		return false, fmt.Errorf("database session is nil")
+	}
+
+	if strings.TrimSpace(table) == "" || strings.TrimSpace(column) == "" {
+		return false, fmt.Errorf("table and column must not be empty")
+	}
+
+	dialect := db.Dialect()
+	args := []interface{}{value}
+	condition := fmt.Sprintf("%s = %s", dialect.Quote(column), dialect.BindVar(1))
+	if idColumn != "" {
+		condition += fmt.Sprintf(" AND %s <> %s", dialect.Quote(idColumn), dialect.BindVar(2))
+		args = append(args, id)
+	}
+	// CASE WHEN EXISTS runs on every dialect, unlike LIMIT (mssql) or a bare EXISTS in the select list
+	query := fmt.Sprintf("SELECT CASE WHEN EXISTS (SELECT 1 FROM %s WHERE %s) THEN 1 ELSE 0 END", dialect.Quote(table), condition)
+
+	// dialects sharing the common bind var use a "$$$" marker that gorm replaces with "?" before executing
+	query = strings.Replace(query, "$$$", "?", -1)
+
+	var row *sql.Row
+	if conn, ok := db.CommonDB().(queryRowContexter); ok {
+		row = conn.QueryRowContext(ctx, query, args...)
+	} else {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		row = db.CommonDB().QueryRow(query, args...)
+	}
+
+	var found int
+	if err := row.Scan(&found); err != nil {
+		return false, err
+	}
+	return found == 1, nil
+}
@@ -0,0 +1,140 @@
+package validator_utils
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	db, err := gorm.Open("mysql", sqlDB)
+	assert.Nil(t, err)
+
+	return db, mock
+}
+
+func TestIsUniqueValidSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `users` WHERE `email` = ?) THEN 1 ELSE 0 END")).
+		WithArgs("john@doe.com").
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(0))
+
+	// act
+	validator := NewValidator()
+	valid := validator.IsUnique(context.Background(), db, "Email", "users", "email", "john@doe.com")
+
+	// assert
+	assert.EqualValues(t, true, valid)
+	assert.Nil(t, validator.Err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestIsUniqueAlreadyExistsSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `users` WHERE `email` = ?) THEN 1 ELSE 0 END")).
+		WithArgs("john@doe.com").
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(1))
+	expectedErr := "Email - Value already exists"
+
+	// act
+	validator := NewValidator()
+	valid := validator.IsUnique(context.Background(), db, "Email", "users", "email", "john@doe.com")
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+	assert.EqualValues(t, false, validator.IsValid())
+}
+
+func TestIsUniqueExcludingSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `users` WHERE `email` = ? AND `id` <> ?) THEN 1 ELSE 0 END")).
+		WithArgs("john@doe.com", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(0))
+
+	// act
+	validator := NewValidator()
+	valid := validator.IsUniqueExcluding(context.Background(), db, "Email", "users", "email", "john@doe.com", "id", 7)
+
+	// assert
+	assert.EqualValues(t, true, valid)
+	assert.Nil(t, validator.Err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestExistsSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `categories` WHERE `id` = ?) THEN 1 ELSE 0 END")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(1))
+
+	// act
+	validator := NewValidator()
+	valid := validator.Exists(context.Background(), db, "CategoryID", "categories", "id", 3)
+
+	// assert
+	assert.EqualValues(t, true, valid)
+	assert.Nil(t, validator.Err)
+}
+
+func TestExistsMissingRowSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `categories` WHERE `id` = ?) THEN 1 ELSE 0 END")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(0))
+	expectedErr := "CategoryID - Value does not exist"
+
+	// act
+	validator := NewValidator()
+	valid := validator.Exists(context.Background(), db, "CategoryID", "categories", "id", 3)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestExistsDatabaseErrorSuccessful(t *testing.T) {
+	// arrange
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CASE WHEN EXISTS (SELECT 1 FROM `categories` WHERE `id` = ?) THEN 1 ELSE 0 END")).
+		WillReturnError(errors.New("connection refused"))
+	expectedErr := "CategoryID - Value could not be checked for existence: connection refused"
+
+	// act
+	validator := NewValidator()
+	valid := validator.Exists(context.Background(), db, "CategoryID", "categories", "id", 3)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
+
+func TestExistsCancelledContextSuccessful(t *testing.T) {
+	// arrange
+	db, _ := newMockDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expectedErr := "CategoryID - Value could not be checked for existence: context canceled"
+
+	// act
+	validator := NewValidator()
+	valid := validator.Exists(ctx, db, "CategoryID", "categories", "id", 3)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, expectedErr, validator.Err.Error())
+}
@@ -0,0 +1,140 @@
+package validator_utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const jsonSchemaURLPrefix = "fs:///"
+
+//JSONSchemaLoader loads json schemas (draft 2020-12) from a file system and caches the compiled result
+type JSONSchemaLoader struct {
+	fsys     fs.FS
+	mu       sync.Mutex
+	compiler *jsonschema.Compiler
+	schemas  map[string]*jsonschema.Schema
+}
+
+//NewJSONSchemaLoader constructor to load schemas from a file system such as an embed.FS
+func NewJSONSchemaLoader(fsys fs.FS) *JSONSchemaLoader {
+	l := &JSONSchemaLoader{
+		fsys:    fsys,
+		schemas: map[string]*jsonschema.Schema{},
+	}
+
+	l.compiler = jsonschema.NewCompiler()
+	l.compiler.Draft = jsonschema.Draft2020
+	l.compiler.AssertFormat = true
+	l.compiler.LoadURL = l.loadURL
+
+	return l
+}
+
+//NewJSONSchemaLoaderFromDir constructor to load schemas from a directory on disk
+func NewJSONSchemaLoaderFromDir(dir string) *JSONSchemaLoader {
+	return NewJSONSchemaLoader(os.DirFS(dir))
+}
+
+//Compile compiles the schema found at path, or returns it from the cache if already compiled
+func (l *JSONSchemaLoader) Compile(path string) (*jsonschema.Schema, error) {
+	path = strings.TrimPrefix(path, "/")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if schema, ok := l.schemas[path]; ok {
+		return schema, nil
+	}
+
+	schema, err := l.compiler.Compile(jsonSchemaURLPrefix + path)
+	if err != nil {
+		return nil, err
+	}
+
+	l.schemas[path] = schema
+	return schema, nil
+}
+
+//loadURL resolves schema urls, including relative $ref urls, against the loader file system
+func (l *JSONSchemaLoader) loadURL(s string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(s, jsonSchemaURLPrefix) {
+		return nil, fmt.Errorf("json schema %s is outside of the loader file system", s)
+	}
+	return l.fsys.Open(strings.TrimPrefix(s, jsonSchemaURLPrefix))
+}
+
+//MatchesJSONSchema method to check if a raw json body is valid against the json schema at schemaPath
+func (v *Validator) MatchesJSONSchema(propertyName string, loader *JSONSchemaLoader, schemaPath string, body []byte) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	return v.MatchesJSONSchemaReader(propertyName, loader, schemaPath, bytes.NewReader(body))
+}
+
+//MatchesJSONSchemaReader method to check if a json stream is valid against the json schema at schemaPath
+func (v *Validator) MatchesJSONSchemaReader(propertyName string, loader *JSONSchemaLoader, schemaPath string, r io.Reader) bool {
+	if v.Err != nil {
+		return false
+	}
+
+	schema, err := loader.Compile(schemaPath)
+	if err != nil {
+		v.Err = fmt.Errorf("%s - Schema %s could not be loaded: %v", propertyName, schemaPath, err)
+		return false
+	}
+
+	// decode numbers as json.Number to keep precision, as expected by the schema validator
+	var doc interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		v.Err = fmt.Errorf("%s - Value must be valid json", propertyName)
+		return false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		v.Err = fmt.Errorf("%s - Value must be valid json", propertyName)
+		return false
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			v.Err = fmt.Errorf("%s - %v", propertyName, err)
+			return false
+		}
+
+		// report the first leaf error, the same way as the other rules report a single error
+		leaf := validationErr
+		for len(leaf.Causes) > 0 {
+			leaf = leaf.Causes[0]
+		}
+		v.Err = fmt.Errorf("%s - Value does not match schema: %s", jsonSchemaPropertyPath(propertyName, leaf.InstanceLocation), leaf.Message)
+		return false
+	}
+
+	return true
+}
+
+//jsonSchemaPropertyPath converts a json pointer such as /items/0/name to PropName.items.0.name
+func jsonSchemaPropertyPath(propertyName string, instanceLocation string) string {
+	if instanceLocation == "" || instanceLocation == "/" {
+		return propertyName
+	}
+
+	segments := strings.Split(strings.TrimPrefix(instanceLocation, "/"), "/")
+	for i, segment := range segments {
+		segment = strings.ReplaceAll(segment, "~1", "/")
+		segments[i] = strings.ReplaceAll(segment, "~0", "~")
+	}
+
+	return propertyName + "." + strings.Join(segments, ".")
+}
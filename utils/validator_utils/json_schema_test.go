@@ -0,0 +1,152 @@
+package validator_utils
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var jsonSchemaFS = fstest.MapFS{
+	"schemas/user.json": &fstest.MapFile{Data: []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["name", "email"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"email": {"type": "string", "format": "email"},
+			"address": {"$ref": "address.json"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`)},
+	"schemas/address.json": &fstest.MapFile{Data: []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["city"],
+		"properties": {
+			"city": {"type": "string"}
+		}
+	}`)},
+}
+
+func TestMatchesJSONSchemaValidSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{"name": "John", "email": "john@doe.com", "address": {"city": "Valletta"}, "tags": ["a"]}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, true, valid)
+	assert.Nil(t, validator.Err)
+}
+
+func TestMatchesJSONSchemaMissingPropertySuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{"name": "John"}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.NotNil(t, validator.Err)
+	assert.EqualValues(t, "Body - Value does not match schema: missing properties: 'email'", validator.Err.Error())
+	assert.EqualValues(t, false, validator.IsValid())
+}
+
+func TestMatchesJSONSchemaNestedPathSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{"name": "John", "email": "john@doe.com", "tags": ["a", 1]}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, "Body.tags.1 - Value does not match schema: expected string, but got number", validator.Err.Error())
+}
+
+func TestMatchesJSONSchemaReferencedSchemaSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := strings.NewReader(`{"name": "John", "email": "john@doe.com", "address": {}}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchemaReader(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, "Body.address - Value does not match schema: missing properties: 'city'", validator.Err.Error())
+}
+
+func TestMatchesJSONSchemaInvalidFormatSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{"name": "John", "email": "not-an-email"}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.True(t, strings.HasPrefix(validator.Err.Error(), "Body.email - Value does not match schema:"))
+}
+
+func TestMatchesJSONSchemaInvalidJSONSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{"name": "John"`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/user.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.EqualValues(t, "Body - Value must be valid json", validator.Err.Error())
+}
+
+func TestMatchesJSONSchemaMissingSchemaSuccessful(t *testing.T) {
+	// arrange
+	propName := "Body"
+	input := []byte(`{}`)
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	validator := NewValidator()
+	valid := validator.MatchesJSONSchema(propName, loader, "schemas/missing.json", input)
+
+	// assert
+	assert.EqualValues(t, false, valid)
+	assert.True(t, strings.HasPrefix(validator.Err.Error(), "Body - Schema schemas/missing.json could not be loaded"))
+}
+
+func TestJSONSchemaLoaderCachesCompiledSchemaSuccessful(t *testing.T) {
+	// arrange
+	loader := NewJSONSchemaLoader(jsonSchemaFS)
+
+	// act
+	first, err := loader.Compile("schemas/user.json")
+	assert.Nil(t, err)
+	second, err := loader.Compile("/schemas/user.json")
+	assert.Nil(t, err)
+
+	// assert
+	assert.True(t, first == second)
+}
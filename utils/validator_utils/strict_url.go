@@ -0,0 +1,200 @@
+package validator_utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// hosts made only of decimal, octal or hex labels, such as 2130706433, 0x7f.1 or 0177.0.0.1
+	regexNumericHost = regexp.MustCompile(`^((0x[0-9a-f]*|[0-9]+)\.?)+$`)
+
+	blockedHostSuffixes = []string{"localhost", "localdomain", "internal"}
+
+	reservedNetworks = mustParseCIDRs(
+		"0.0.0.0/8",       // current network
+		"100.64.0.0/10",   // carrier grade nat
+		"192.0.0.0/24",    // ietf protocol assignments
+		"192.0.2.0/24",    // test-net-1
+		"198.18.0.0/15",   // benchmarking
+		"198.51.100.0/24", // test-net-2
+		"203.0.113.0/24",  // test-net-3
+		"240.0.0.0/4",     // reserved and broadcast
+		"64:ff9b::/96",    // nat64, may translate to private ipv4
+		"64:ff9b:1::/48",  // local-use nat64
+		"100::/64",        // discard only
+		"2001::/23",       // ietf protocol assignments
+		"2001:db8::/32",   // documentation
+		"2002::/16",       // 6to4, may embed private ipv4
+	)
+)
+
+//Resolver resolves a host to its ip addresses, net.DefaultResolver satisfies this interface
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+//StrictURLOptions options used by IsValidStrictURL
+type StrictURLOptions struct {
+	// Resolver used to resolve the url host, defaults to net.DefaultResolver
+	Resolver Resolver
+	// AllowedPorts ports that can be targeted, defaults to 80 and 443
+	AllowedPorts []int
+	// MustBeHTTPS enforces the url to be https
+	MustBeHTTPS bool
+}
+
+//IsValidStrictURL method to check that the inputted value is a URL that is safe to call from the server.
+//It rejects credentials, disallowed ports, ip literals in non canonical encodings and hosts
+//resolving to loopback, link-local, private, metadata or otherwise reserved addresses.
+//On success it returns the vetted ip addresses, which the caller should dial instead of resolving the host again.
+func (v *Validator) IsValidStrictURL(ctx context.Context, propertyName string, inputtedURL string, options *StrictURLOptions) ([]net.IP, bool) {
+	if v.Err != nil {
+		return nil, false
+	}
+
+	if options == nil {
+		options = &StrictURLOptions{}
+	}
+
+	u, err := url.Parse(inputtedURL)
+	if err != nil || u.Host == "" || u.Opaque != "" {
+		v.Err = fmt.Errorf("%s - Value is not a valid url", propertyName)
+		return nil, false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		v.Err = fmt.Errorf("%s - Value is not a valid url", propertyName)
+		return nil, false
+	}
+
+	if options.MustBeHTTPS && scheme != "https" {
+		v.Err = fmt.Errorf("%s - Value is not a valid https url", propertyName)
+		return nil, false
+	}
+
+	if u.User != nil {
+		v.Err = fmt.Errorf("%s - Value must not contain credentials", propertyName)
+		return nil, false
+	}
+
+	// validate port
+	port := 80
+	if scheme == "https" {
+		port = 443
+	}
+	if p := u.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil {
+			v.Err = fmt.Errorf("%s - Value is not a valid url", propertyName)
+			return nil, false
+		}
+	}
+	if !isAllowedPort(port, options.AllowedPorts) {
+		v.Err = fmt.Errorf("%s - Value port %d is not allowed", propertyName, port)
+		return nil, false
+	}
+
+	// validate host
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" || strings.Contains(host, "%") {
+		v.Err = fmt.Errorf("%s - Value is not a valid url", propertyName)
+		return nil, false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			v.Err = fmt.Errorf("%s - Value must not point to a private or reserved address", propertyName)
+			return nil, false
+		}
+		return []net.IP{ip}, true
+	}
+
+	if regexNumericHost.MatchString(host) {
+		v.Err = fmt.Errorf("%s - Value must not contain an ip address in a non standard format", propertyName)
+		return nil, false
+	}
+
+	for _, suffix := range blockedHostSuffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			v.Err = fmt.Errorf("%s - Value must not point to a private or reserved address", propertyName)
+			return nil, false
+		}
+	}
+
+	// resolve host, every resolved address must be public to avoid partial dns rebinding
+	var resolver Resolver = net.DefaultResolver
+	if options.Resolver != nil {
+		resolver = options.Resolver
+	}
+
+	addresses, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addresses) == 0 {
+		v.Err = fmt.Errorf("%s - Value host could not be resolved", propertyName)
+		return nil, false
+	}
+
+	ips := make([]net.IP, 0, len(addresses))
+	for _, address := range addresses {
+		if address.Zone != "" || !isPublicIP(address.IP) {
+			v.Err = fmt.Errorf("%s - Value must not point to a private or reserved address", propertyName)
+			return nil, false
+		}
+		ips = append(ips, address.IP)
+	}
+
+	return ips, true
+}
+
+//isAllowedPort checks whether port is in the allowed list, defaulting to the http and https ports
+func isAllowedPort(port int, allowedPorts []int) bool {
+	if len(allowedPorts) == 0 {
+		allowedPorts = []int{80, 443}
+	}
+	for _, allowedPort := range allowedPorts {
+		if port == allowedPort {
+			return true
+		}
+	}
+	return false
+}
+
+//isPublicIP checks whether the ip is globally routable
+func isPublicIP(ip net.IP) bool {
+	// unwrap ipv4 mapped ipv6 addresses such as ::ffff:127.0.0.1
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+//mustParseCIDRs parses the cidrs and panics on failure
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
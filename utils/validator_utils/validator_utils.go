@@ -2,6 +2,7 @@ package validator_utils
 
 import (
 	"fmt"
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"gopkg.in/mgo.v2/bson"
 	"net/url"
 	"regexp"
@@ -13,7 +14,8 @@ import (
 
 //Validator empty struct
 type Validator struct {
-	Err error
+	Err   error
+	clock date_utils.Clock
 }
 
 var (
@@ -27,6 +29,19 @@ func NewValidator() *Validator {
 	return &Validator{}
 }
 
+//SetClock replaces the clock used by the date rules, which otherwise use date_utils.GetClock
+func (v *Validator) SetClock(clock date_utils.Clock) {
+	v.clock = clock
+}
+
+//now returns the current time of the validator clock, falling back to date_utils.GetClock when unset
+func (v *Validator) now() time.Time {
+	if v.clock == nil {
+		return date_utils.GetClock().Now()
+	}
+	return v.clock.Now()
+}
+
 //IsNotEmpty method to check if input is not empty
 func (v *Validator) IsNotEmpty(propertyName string, value string) bool {
 	if v.Err != nil {
@@ -166,39 +181,24 @@ func (v *Validator) MaxLength(propertyName string, value string, maxLength int)
 }
 
 //MustBeGreaterThan method to check whether value is greater than
+//
+// Deprecated: use GreaterThan, which works for any ordered type
 func (v *Validator) MustBeGreaterThan(propertyName string, high, value int) bool {
-	if v.Err != nil {
-		return false
-	}
-	if value <= high {
-		v.Err = fmt.Errorf("%s - Value must be greater than %d", propertyName, high)
-		return false
-	}
-	return true
+	return GreaterThan(v, propertyName, value, high)
 }
 
 //MustBeGreaterThanFloat64 method to check whether value is greater than
+//
+// Deprecated: use GreaterThan, which works for any ordered type
 func (v *Validator) MustBeGreaterThanFloat64(propertyName string, high, value float64) bool {
-	if v.Err != nil {
-		return false
-	}
-	if value <= high {
-		v.Err = fmt.Errorf("%s - Value must be greater than %v", propertyName, high)
-		return false
-	}
-	return true
+	return GreaterThan(v, propertyName, value, high)
 }
 
 //MustBeGreaterThanInt64 method to check whether value is greater than
+//
+// Deprecated: use GreaterThan, which works for any ordered type
 func (v *Validator) MustBeGreaterThanInt64(propertyName string, high, value int64) bool {
-	if v.Err != nil {
-		return false
-	}
-	if value <= high {
-		v.Err = fmt.Errorf("%s - Value must be greater than %v", propertyName, high)
-		return false
-	}
-	return true
+	return GreaterThan(v, propertyName, value, high)
 }
 
 //ContainsList method to check where list is in allowed list
@@ -358,7 +358,7 @@ func (v *Validator) DateMustNotBeInFuture(propertyName string, value time.Time)
 		return false
 	}
 
-	if value.Sub(time.Now().UTC()) > 0 {
+	if value.Sub(v.now().UTC()) > 0 {
 		v.Err = fmt.Errorf("%s - Value cannot be in the future", propertyName)
 		return false
 	}
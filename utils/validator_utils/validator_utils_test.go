@@ -1,6 +1,7 @@
 package validator_utils
 
 import (
+	"github.com/lelinu/api_utils/utils/date_utils"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -795,6 +796,44 @@ func TestDateMustNotBeInFutureValidSuccessful(t *testing.T){
 	assert.Nil(t, validator.Err)
 }
 
+func TestDateMustNotBeInFutureWithClockSuccessful(t *testing.T){
+	// arrange
+	propName := "DateMustBeBefore"
+	now := time.Date(2020, 3, 4, 12, 30, 0, 0, time.UTC)
+	clock := date_utils.NewFakeClock(now)
+	input := now.Add(time.Minute)
+
+	// act
+	validator := NewValidator()
+	validator.SetClock(clock)
+	inFuture := validator.DateMustNotBeInFuture(propName, input)
+	validator.Err = nil
+	clock.Advance(time.Hour)
+	inPast := validator.DateMustNotBeInFuture(propName, input)
+
+	// assert
+	assert.EqualValues(t, false, inFuture)
+	assert.EqualValues(t, true, inPast)
+	assert.Nil(t, validator.Err)
+}
+
+func TestDateMustNotBeInFutureWithPackageClockSuccessful(t *testing.T){
+	// arrange
+	propName := "DateMustBeBefore"
+	now := time.Date(2020, 3, 4, 12, 30, 0, 0, time.UTC)
+	date_utils.SetClock(date_utils.NewFakeClock(now))
+	defer date_utils.SetClock(nil)
+	input := now.Add(time.Minute)
+
+	// act
+	validator := NewValidator()
+	inFuture := validator.DateMustNotBeInFuture(propName, input)
+
+	// assert
+	assert.EqualValues(t, false, inFuture)
+	assert.NotNil(t, validator.Err)
+}
+
 /// NEW
 func TestIsValidBsonIDSuccessful(t *testing.T){
 	// arrange